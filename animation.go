@@ -0,0 +1,473 @@
+package discordterm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"mime"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// MaxAnimationFrames is the largest number of frames an animation can have
+const MaxAnimationFrames = 1000
+
+// DefaultFrameDelay is used for frames that do not specify a usable delay
+const DefaultFrameDelay = 100 * time.Millisecond
+
+// Animation is a decoded animated image with every frame fully composited
+type Animation struct {
+	Frames []image.Image
+	Delays []time.Duration
+
+	// LoopCount is the number of times the animation should play.
+	// Zero means forever.
+	LoopCount int
+}
+
+// DecodeAnimation decodes an animated GIF or APNG.
+// Images that are not animated are returned as a single frame animation.
+func DecodeAnimation(data []byte, contentType string) (*Animation, error) {
+	format, err := SniffImageFormat(contentType, data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case "gif":
+		return decodeGIFAnimation(data)
+	case "png":
+		if isAPNG(data) {
+			return decodeAPNG(data)
+		}
+	}
+
+	img, _, err := DecodeImageData(data, contentType)
+	if err != nil {
+		return nil, err
+	}
+	return &Animation{
+		Frames:    []image.Image{img},
+		Delays:    []time.Duration{0},
+		LoopCount: 1,
+	}, nil
+}
+
+func frameDelay(d time.Duration) time.Duration {
+	// Browsers treat tiny delays as the default delay, so do the same
+	if d < 20*time.Millisecond {
+		return DefaultFrameDelay
+	}
+	return d
+}
+
+func decodeGIFAnimation(data []byte) (*Animation, error) {
+	cfg, err := gif.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not read gif header: %v", err)
+	}
+	if err := CheckImageConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	g, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode gif image: %v", err)
+	}
+	if len(g.Image) == 0 {
+		return nil, errors.New("gif has no frames")
+	}
+	if len(g.Image) > MaxAnimationFrames {
+		return nil, fmt.Errorf("%v: gif has %d frames", ErrImageTooLarge, len(g.Image))
+	}
+
+	anim := &Animation{}
+	switch {
+	case g.LoopCount == 0:
+		anim.LoopCount = 0
+	case g.LoopCount < 0:
+		anim.LoopCount = 1
+	default:
+		anim.LoopCount = g.LoopCount + 1
+	}
+
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	canvas := image.NewRGBA(bounds)
+	for i, frame := range g.Image {
+		var previous *image.RGBA
+		if g.Disposal != nil && g.Disposal[i] == gif.DisposalPrevious {
+			previous = image.NewRGBA(bounds)
+			copy(previous.Pix, canvas.Pix)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+
+		out := image.NewRGBA(bounds)
+		copy(out.Pix, canvas.Pix)
+		anim.Frames = append(anim.Frames, out)
+		anim.Delays = append(anim.Delays, frameDelay(time.Duration(g.Delay[i])*10*time.Millisecond))
+
+		if g.Disposal == nil {
+			continue
+		}
+		switch g.Disposal[i] {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.ZP, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+
+	return anim, nil
+}
+
+// APNG chunk layout
+// https://wiki.mozilla.org/APNG_Specification
+const (
+	pngSignature = "\x89PNG\r\n\x1a\n"
+
+	apngDisposeNone       = 0
+	apngDisposeBackground = 1
+	apngDisposePrevious   = 2
+
+	apngBlendSource = 0
+)
+
+type pngChunk struct {
+	typ  string
+	data []byte
+}
+
+type apngFrame struct {
+	width, height    int
+	xOffset, yOffset int
+	delay            time.Duration
+	disposeOp        byte
+	blendOp          byte
+	data             [][]byte
+}
+
+func readPNGChunks(data []byte) ([]pngChunk, error) {
+	if !bytes.HasPrefix(data, []byte(pngSignature)) {
+		return nil, errors.New("not a png image")
+	}
+	data = data[len(pngSignature):]
+
+	var chunks []pngChunk
+	for len(data) >= 12 {
+		length := int(binary.BigEndian.Uint32(data[:4]))
+		if length < 0 || len(data) < 12+length {
+			return nil, errors.New("truncated png chunk")
+		}
+		chunks = append(chunks, pngChunk{
+			typ:  string(data[4:8]),
+			data: data[8 : 8+length],
+		})
+		data = data[12+length:]
+	}
+	return chunks, nil
+}
+
+func writePNGChunk(buf *bytes.Buffer, typ string, data []byte) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], uint32(len(data)))
+	buf.Write(b[:])
+
+	crc := crc32.NewIEEE()
+	crc.Write([]byte(typ))
+	crc.Write(data)
+
+	buf.WriteString(typ)
+	buf.Write(data)
+	binary.BigEndian.PutUint32(b[:], crc.Sum32())
+	buf.Write(b[:])
+}
+
+// isAPNG reports whether PNG data contains an animation control chunk
+func isAPNG(data []byte) bool {
+	chunks, err := readPNGChunks(data)
+	if err != nil {
+		return false
+	}
+	for _, c := range chunks {
+		switch c.typ {
+		case "acTL":
+			return true
+		case "IDAT":
+			return false
+		}
+	}
+	return false
+}
+
+func decodeAPNG(data []byte) (*Animation, error) {
+	chunks, err := readPNGChunks(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		ihdr      []byte
+		shared    []pngChunk
+		frames    []*apngFrame
+		current   *apngFrame
+		numPlays  int
+		seenIDAT  bool
+		numFrames int
+	)
+
+	for _, c := range chunks {
+		switch c.typ {
+		case "IHDR":
+			if len(c.data) != 13 {
+				return nil, errors.New("invalid png header")
+			}
+			ihdr = c.data
+		case "acTL":
+			if len(c.data) != 8 {
+				return nil, errors.New("invalid apng animation control chunk")
+			}
+			numFrames = int(binary.BigEndian.Uint32(c.data[:4]))
+			numPlays = int(binary.BigEndian.Uint32(c.data[4:]))
+		case "fcTL":
+			if len(c.data) != 26 {
+				return nil, errors.New("invalid apng frame control chunk")
+			}
+			num := binary.BigEndian.Uint16(c.data[20:22])
+			den := binary.BigEndian.Uint16(c.data[22:24])
+			if den == 0 {
+				den = 100
+			}
+			current = &apngFrame{
+				width:     int(binary.BigEndian.Uint32(c.data[4:8])),
+				height:    int(binary.BigEndian.Uint32(c.data[8:12])),
+				xOffset:   int(binary.BigEndian.Uint32(c.data[12:16])),
+				yOffset:   int(binary.BigEndian.Uint32(c.data[16:20])),
+				delay:     frameDelay(time.Duration(num) * time.Second / time.Duration(den)),
+				disposeOp: c.data[24],
+				blendOp:   c.data[25],
+			}
+			frames = append(frames, current)
+		case "IDAT":
+			seenIDAT = true
+			// The default image is only part of the animation
+			// When a frame control chunk precedes it
+			if current != nil {
+				current.data = append(current.data, c.data)
+			}
+		case "fdAT":
+			if current != nil && len(c.data) > 4 {
+				current.data = append(current.data, c.data[4:])
+			}
+		case "IEND":
+		default:
+			if !seenIDAT {
+				shared = append(shared, c)
+			}
+		}
+	}
+
+	if ihdr == nil || len(frames) == 0 {
+		return nil, errors.New("apng has no frames")
+	}
+	if numFrames > MaxAnimationFrames || len(frames) > MaxAnimationFrames {
+		return nil, fmt.Errorf("%v: apng has %d frames", ErrImageTooLarge, len(frames))
+	}
+
+	width := int(binary.BigEndian.Uint32(ihdr[0:4]))
+	height := int(binary.BigEndian.Uint32(ihdr[4:8]))
+	if err := CheckImageConfig(image.Config{Width: width, Height: height}); err != nil {
+		return nil, err
+	}
+
+	anim := &Animation{LoopCount: numPlays}
+	bounds := image.Rect(0, 0, width, height)
+	canvas := image.NewRGBA(bounds)
+
+	for i, f := range frames {
+		if len(f.data) == 0 {
+			continue
+		}
+		if err := CheckImageConfig(image.Config{Width: f.width, Height: f.height}); err != nil {
+			return nil, err
+		}
+
+		// Rebuild a standalone png from the frame data
+		// So that it can be decoded by the standard library
+		var buf bytes.Buffer
+		buf.WriteString(pngSignature)
+		frameHeader := append([]byte{}, ihdr...)
+		binary.BigEndian.PutUint32(frameHeader[0:4], uint32(f.width))
+		binary.BigEndian.PutUint32(frameHeader[4:8], uint32(f.height))
+		writePNGChunk(&buf, "IHDR", frameHeader)
+		for _, c := range shared {
+			if c.typ == "acTL" || c.typ == "fcTL" {
+				continue
+			}
+			writePNGChunk(&buf, c.typ, c.data)
+		}
+		for _, d := range f.data {
+			writePNGChunk(&buf, "IDAT", d)
+		}
+		writePNGChunk(&buf, "IEND", nil)
+
+		img, err := png.Decode(&buf)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode apng frame %d: %v", i, err)
+		}
+
+		disposeOp := f.disposeOp
+		if i == 0 && disposeOp == apngDisposePrevious {
+			disposeOp = apngDisposeBackground
+		}
+
+		var previous *image.RGBA
+		if disposeOp == apngDisposePrevious {
+			previous = image.NewRGBA(bounds)
+			copy(previous.Pix, canvas.Pix)
+		}
+
+		region := image.Rect(f.xOffset, f.yOffset, f.xOffset+f.width, f.yOffset+f.height)
+		op := draw.Over
+		if f.blendOp == apngBlendSource {
+			op = draw.Src
+		}
+		draw.Draw(canvas, region, img, img.Bounds().Min, op)
+
+		out := image.NewRGBA(bounds)
+		copy(out.Pix, canvas.Pix)
+		anim.Frames = append(anim.Frames, out)
+		anim.Delays = append(anim.Delays, f.delay)
+
+		switch disposeOp {
+		case apngDisposeBackground:
+			draw.Draw(canvas, region, image.Transparent, image.ZP, draw.Src)
+		case apngDisposePrevious:
+			canvas = previous
+		}
+	}
+
+	if len(anim.Frames) == 0 {
+		return nil, errors.New("apng has no frames")
+	}
+	return anim, nil
+}
+
+// PlayAnimationComplex plays an animation in place in the terminal.
+// The animation is played at most loops times, or the number of times
+// Specified by the animation if that is lower. Playback stops early
+// When stop is closed.
+func (c *Client) PlayAnimationComplex(anim *Animation, conf *Config, loops int, stop <-chan struct{}) error {
+	if conf == nil {
+		conf = NewConfig()
+	}
+
+	// Render every frame up front so that playback is smooth
+	frames := make([][]byte, len(anim.Frames))
+	for i, img := range anim.Frames {
+		text, err := c.renderImage(img, conf)
+		if err != nil {
+			return err
+		}
+		frames[i] = text
+	}
+
+	if anim.LoopCount > 0 && (loops <= 0 || anim.LoopCount < loops) {
+		loops = anim.LoopCount
+	}
+	if len(frames) == 1 {
+		loops = 1
+	}
+
+	height := bytes.Count(frames[0], []byte("\n"))
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	first := true
+	for loop := 0; loops <= 0 || loop < loops; loop++ {
+		for i, text := range frames {
+			select {
+			case <-stop:
+				return nil
+			case <-timer.C:
+			}
+
+			if !first {
+				// Move the cursor back to the top of the previous frame
+				fmt.Printf("\x1b[%dA\r", height)
+			}
+			first = false
+			os.Stdout.Write(text)
+
+			timer.Reset(anim.Delays[i])
+		}
+	}
+	return nil
+}
+
+// PlayImageURLComplex downloads an image and plays it in place if it is animated.
+// Playback can be stopped with Ctrl-C.
+func (c *Client) PlayImageURLComplex(path string, conf *Config) error {
+	if conf == nil {
+		conf = NewConfig()
+	}
+
+	data, contentType, err := FetchImageData(path)
+	if err != nil {
+		return err
+	}
+	anim, err := DecodeAnimation(data, contentType)
+	if err != nil {
+		return fmt.Errorf("could not play image %s: %v", path, err)
+	}
+
+	// Stop playback on interrupt instead of exiting the program
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-interrupt:
+			close(stop)
+		case <-done:
+		}
+	}()
+
+	return c.PlayAnimationComplex(anim, conf, conf.AnimationLoops, stop)
+}
+
+// PlayImageURL plays an animated image from URL with the client settings
+func (c *Client) PlayImageURL(path string) error {
+	return c.PlayImageURLComplex(path, c.Conf)
+}
+
+// IsAnimatedFilename reports whether a filename could contain an animation
+func IsAnimatedFilename(name string) bool {
+	name = strings.ToLower(name)
+	return strings.HasSuffix(name, ".gif") || strings.HasSuffix(name, ".png") ||
+		strings.HasSuffix(name, ".apng")
+}
+
+// IsAnimatedAttachment reports whether an attachment could contain an animation,
+// By its filename or its content type
+func IsAnimatedAttachment(a *discordgo.MessageAttachment) bool {
+	if IsAnimatedFilename(a.Filename) {
+		return true
+	}
+	mediaType, _, _ := mime.ParseMediaType(a.ContentType)
+	return mediaType == "image/gif" || mediaType == "image/png" || mediaType == "image/apng"
+}
@@ -0,0 +1,332 @@
+package discordterm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	. "github.com/logrusorgru/aurora"
+)
+
+// auditLogPageSize is the most audit log entries discord returns at once
+const auditLogPageSize = 100
+
+// AuditAction is a named audit log action type
+type AuditAction struct {
+	Name   string
+	Action discordgo.AuditLogAction
+}
+
+// AuditActions lists the audit log action types by the names discord
+// Gives them in its documentation
+var AuditActions = []AuditAction{
+	{"guild_update", discordgo.AuditLogActionGuildUpdate},
+	{"channel_create", discordgo.AuditLogActionChannelCreate},
+	{"channel_update", discordgo.AuditLogActionChannelUpdate},
+	{"channel_delete", discordgo.AuditLogActionChannelDelete},
+	{"channel_overwrite_create", discordgo.AuditLogActionChannelOverwriteCreate},
+	{"channel_overwrite_update", discordgo.AuditLogActionChannelOverwriteUpdate},
+	{"channel_overwrite_delete", discordgo.AuditLogActionChannelOverwriteDelete},
+	{"member_kick", discordgo.AuditLogActionMemberKick},
+	{"member_prune", discordgo.AuditLogActionMemberPrune},
+	{"member_ban_add", discordgo.AuditLogActionMemberBanAdd},
+	{"member_ban_remove", discordgo.AuditLogActionMemberBanRemove},
+	{"member_update", discordgo.AuditLogActionMemberUpdate},
+	{"member_role_update", discordgo.AuditLogActionMemberRoleUpdate},
+	{"member_move", discordgo.AuditLogActionMemberMove},
+	{"member_disconnect", discordgo.AuditLogActionMemberDisconnect},
+	{"bot_add", discordgo.AuditLogActionBotAdd},
+	{"role_create", discordgo.AuditLogActionRoleCreate},
+	{"role_update", discordgo.AuditLogActionRoleUpdate},
+	{"role_delete", discordgo.AuditLogActionRoleDelete},
+	{"invite_create", discordgo.AuditLogActionInviteCreate},
+	{"invite_update", discordgo.AuditLogActionInviteUpdate},
+	{"invite_delete", discordgo.AuditLogActionInviteDelete},
+	{"webhook_create", discordgo.AuditLogActionWebhookCreate},
+	{"webhook_update", discordgo.AuditLogActionWebhookUpdate},
+	{"webhook_delete", discordgo.AuditLogActionWebhookDelete},
+	{"emoji_create", discordgo.AuditLogActionEmojiCreate},
+	{"emoji_update", discordgo.AuditLogActionEmojiUpdate},
+	{"emoji_delete", discordgo.AuditLogActionEmojiDelete},
+	{"message_delete", discordgo.AuditLogActionMessageDelete},
+	{"message_bulk_delete", discordgo.AuditLogActionMessageBulkDelete},
+	{"message_pin", discordgo.AuditLogActionMessagePin},
+	{"message_unpin", discordgo.AuditLogActionMessageUnpin},
+	{"integration_create", discordgo.AuditLogActionIntegrationCreate},
+	{"integration_update", discordgo.AuditLogActionIntegrationUpdate},
+	{"integration_delete", discordgo.AuditLogActionIntegrationDelete},
+	{"stage_instance_create", discordgo.AuditLogActionStageInstanceCreate},
+	{"stage_instance_update", discordgo.AuditLogActionStageInstanceUpdate},
+	{"stage_instance_delete", discordgo.AuditLogActionStageInstanceDelete},
+	{"sticker_create", discordgo.AuditLogActionStickerCreate},
+	{"sticker_update", discordgo.AuditLogActionStickerUpdate},
+	{"sticker_delete", discordgo.AuditLogActionStickerDelete},
+	{"guild_scheduled_event_create", discordgo.AuditLogGuildScheduledEventCreate},
+	{"guild_scheduled_event_update", discordgo.AuditLogGuildScheduledEventUpdate},
+	{"guild_scheduled_event_delete", discordgo.AuditLogGuildScheduledEventDelete},
+	{"thread_create", discordgo.AuditLogActionThreadCreate},
+	{"thread_update", discordgo.AuditLogActionThreadUpdate},
+	{"thread_delete", discordgo.AuditLogActionThreadDelete},
+	{"application_command_permission_update", discordgo.AuditLogActionApplicationCommandPermissionUpdate},
+	{"auto_moderation_rule_create", discordgo.AuditLogActionAutoModerationRuleCreate},
+	{"auto_moderation_rule_update", discordgo.AuditLogActionAutoModerationRuleUpdate},
+	{"auto_moderation_rule_delete", discordgo.AuditLogActionAutoModerationRuleDelete},
+	{"auto_moderation_block_message", discordgo.AuditLogActionAutoModerationBlockMessage},
+}
+
+// AuditActionName returns the name of an audit log action type
+func AuditActionName(a discordgo.AuditLogAction) string {
+	for _, action := range AuditActions {
+		if action.Action == a {
+			return action.Name
+		}
+	}
+	return "action_" + strconv.Itoa(int(a))
+}
+
+// ParseAuditAction returns the audit log action type with the given name or number.
+// Names may use dashes or spaces in place of underscores.
+func ParseAuditAction(name string) (discordgo.AuditLogAction, error) {
+	if n, err := strconv.Atoi(name); err == nil {
+		return discordgo.AuditLogAction(n), nil
+	}
+	n := strings.NewReplacer("-", "_", " ", "_").Replace(strings.ToLower(strings.TrimSpace(name)))
+	for _, action := range AuditActions {
+		if action.Name == n {
+			return action.Action, nil
+		}
+	}
+	return 0, errors.New("unknown audit log action: " + name)
+}
+
+// AuditLog fetches up to limit entries of a guild's audit log, newest first.
+// userID and action filter the entries when they are not empty or zero.
+func (c *Client) AuditLog(guildID, userID string, action discordgo.AuditLogAction, limit int) (*discordgo.GuildAuditLog, error) {
+	all := &discordgo.GuildAuditLog{}
+	before := ""
+	for len(all.AuditLogEntries) < limit {
+		page, err := c.Cli.GuildAuditLog(guildID, userID, before, int(action), minInt(limit-len(all.AuditLogEntries), auditLogPageSize))
+		if err != nil {
+			return nil, err
+		}
+		all.AuditLogEntries = append(all.AuditLogEntries, page.AuditLogEntries...)
+		all.Users = append(all.Users, page.Users...)
+		all.Webhooks = append(all.Webhooks, page.Webhooks...)
+		all.Integrations = append(all.Integrations, page.Integrations...)
+		if len(page.AuditLogEntries) < auditLogPageSize {
+			break
+		}
+		before = page.AuditLogEntries[len(page.AuditLogEntries)-1].ID
+	}
+	return all, nil
+}
+
+// AuditRecord is an audit log entry with its IDs resolved to names
+type AuditRecord struct {
+	ID         string                      `json:"id"`
+	Time       time.Time                   `json:"time"`
+	Action     discordgo.AuditLogAction    `json:"action"`
+	ActionName string                      `json:"action_name"`
+	UserID     string                      `json:"user_id"`
+	User       string                      `json:"user"`
+	TargetID   string                      `json:"target_id,omitempty"`
+	Target     string                      `json:"target,omitempty"`
+	Reason     string                      `json:"reason,omitempty"`
+	Changes    []*discordgo.AuditLogChange `json:"changes,omitempty"`
+	Options    *discordgo.AuditLogOptions  `json:"options,omitempty"`
+}
+
+// AuditRecords resolves the IDs of a guild's audit log entries to names
+func (c *Client) AuditRecords(guildID string, log *discordgo.GuildAuditLog) []*AuditRecord {
+	records := []*AuditRecord{}
+	for _, e := range log.AuditLogEntries {
+		r := &AuditRecord{
+			ID:       e.ID,
+			UserID:   e.UserID,
+			User:     c.auditUserName(guildID, e.UserID, log),
+			TargetID: e.TargetID,
+			Reason:   e.Reason,
+			Changes:  e.Changes,
+			Options:  e.Options,
+		}
+		r.Time, _ = discordgo.SnowflakeTimestamp(e.ID)
+		if e.ActionType != nil {
+			r.Action = *e.ActionType
+		}
+		r.ActionName = AuditActionName(r.Action)
+		if e.TargetID != "" {
+			r.Target = c.auditTargetName(guildID, r.Action, e.TargetID, log)
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+// auditUserName returns the name of a user in an audit log
+func (c *Client) auditUserName(guildID, userID string, log *discordgo.GuildAuditLog) string {
+	for _, u := range log.Users {
+		if u.ID == userID {
+			return u.Username + "#" + u.Discriminator
+		}
+	}
+	if m, err := c.Cli.State.Member(guildID, userID); err == nil {
+		return m.User.Username + "#" + m.User.Discriminator
+	}
+	return userID
+}
+
+// auditTargetName returns the name of the target of an audit log entry
+// Based on the kind of object the action applies to
+func (c *Client) auditTargetName(guildID string, action discordgo.AuditLogAction, targetID string, log *discordgo.GuildAuditLog) string {
+	switch {
+	// Bulk deletes target the channel the messages were deleted from
+	case action >= 10 && action < 20, action >= 110 && action < 120, action == discordgo.AuditLogActionMessageBulkDelete:
+		if ch, err := c.Cli.State.Channel(targetID); err == nil {
+			return "#" + ch.Name
+		}
+	case action >= 20 && action < 30, action >= 72 && action < 80:
+		return c.auditUserName(guildID, targetID, log)
+	case action >= 30 && action < 40:
+		if guild, err := c.Cli.State.Guild(guildID); err == nil {
+			if role := guildRole(guild, targetID); role != nil {
+				return "@" + role.Name
+			}
+		}
+	case action >= 50 && action < 60:
+		for _, w := range log.Webhooks {
+			if w.ID == targetID {
+				return w.Name
+			}
+		}
+	case action >= 60 && action < 70:
+		if emoji, err := c.Cli.State.Emoji(guildID, targetID); err == nil {
+			return ":" + emoji.Name + ":"
+		}
+	case action >= 80 && action < 83:
+		for _, i := range log.Integrations {
+			if i.ID == targetID {
+				return i.Name
+			}
+		}
+	}
+	return targetID
+}
+
+// FormatAuditChange formats a change of an audit log entry as "key: before → after"
+func FormatAuditChange(change *discordgo.AuditLogChange) string {
+	key := ""
+	if change.Key != nil {
+		key = string(*change.Key)
+	}
+
+	switch key {
+	// Roles added to or removed from a member are lists of partial roles
+	case "$add", "$remove":
+		sign := "+"
+		if key == "$remove" {
+			sign = "-"
+		}
+		value := change.NewValue
+		if value == nil {
+			value = change.OldValue
+		}
+		names := []string{}
+		if roles, ok := value.([]interface{}); ok {
+			for _, r := range roles {
+				if role, ok := r.(map[string]interface{}); ok {
+					names = append(names, sign+fmt.Sprint(role["name"]))
+				}
+			}
+		}
+		return "roles: " + strings.Join(names, ", ")
+
+	// Permissions are shown as the names of the permissions that changed
+	case "permissions", "allow", "deny":
+		before, okBefore := auditPermissions(change.OldValue)
+		after, okAfter := auditPermissions(change.NewValue)
+		if okBefore || okAfter {
+			changed := []string{}
+			for _, name := range PermissionNames(after &^ before) {
+				changed = append(changed, "+"+name)
+			}
+			for _, name := range PermissionNames(before &^ after) {
+				changed = append(changed, "-"+name)
+			}
+			return key + ": " + strings.Join(changed, ", ")
+		}
+	}
+
+	return fmt.Sprintf("%s: %s → %s", key, formatAuditValue(change.OldValue), formatAuditValue(change.NewValue))
+}
+
+// auditPermissions reads a permission bitfield from an audit log value,
+// Which may be a number or a string
+func auditPermissions(v interface{}) (int64, bool) {
+	switch p := v.(type) {
+	case float64:
+		return int64(p), true
+	case string:
+		n, err := strconv.ParseInt(p, 10, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// formatAuditValue formats a value of an audit log change
+func formatAuditValue(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return "none"
+	case string:
+		return strconv.Quote(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+		return string(data)
+	}
+}
+
+// PrintAuditRecords prints audit log records with their changes
+func (c *Client) PrintAuditRecords(records []*AuditRecord, conf *Config) {
+	for _, r := range records {
+		when := r.Time.Local().Format("2006-01-02 15:04:05")
+		action := strings.Replace(r.ActionName, "_", " ", -1)
+		if conf.ColorText {
+			fmt.Println(Blue(when), Green(r.User), Brown(action), Cyan(r.Target))
+		} else {
+			fmt.Println(when, r.User, action, r.Target)
+		}
+		if r.Reason != "" {
+			fmt.Println("    reason:", r.Reason)
+		}
+		if o := r.Options; o != nil {
+			if o.Count != "" {
+				fmt.Println("    count:", o.Count)
+			}
+			if o.ChannelID != "" {
+				channel := o.ChannelID
+				if ch, err := c.Cli.State.Channel(o.ChannelID); err == nil {
+					channel = "#" + ch.Name
+				}
+				fmt.Println("    channel:", channel)
+			}
+			if o.DeleteMemberDays != "" {
+				fmt.Println("    inactive for:", o.DeleteMemberDays, "days,", o.MembersRemoved, "members removed")
+			}
+			if o.RoleName != "" {
+				fmt.Println("    role:", o.RoleName)
+			}
+		}
+		for _, change := range r.Changes {
+			fmt.Println("    " + FormatAuditChange(change))
+		}
+	}
+}
@@ -0,0 +1,756 @@
+package discordterm
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// BackupVersion is the version of the backup file format written by this client.
+// Backups with a newer version can not be read.
+const BackupVersion = 1
+
+// Backup is a snapshot of a guild's settings, roles, channels and emojis
+type Backup struct {
+	Version   int              `json:"version"`
+	CreatedAt time.Time        `json:"created_at"`
+	Guild     *BackupGuild     `json:"guild"`
+	Roles     []*BackupRole    `json:"roles"`
+	Channels  []*BackupChannel `json:"channels"`
+	Emojis    []*BackupEmoji   `json:"emojis"`
+}
+
+// BackupGuild holds the settings of a guild.
+// Icon is a data URI so that it can be uploaded again.
+type BackupGuild struct {
+	ID                          string `json:"id"`
+	Name                        string `json:"name"`
+	IconHash                    string `json:"icon_hash,omitempty"`
+	Icon                        string `json:"icon,omitempty"`
+	VerificationLevel           int    `json:"verification_level"`
+	DefaultMessageNotifications int    `json:"default_message_notifications"`
+	ExplicitContentFilter       int    `json:"explicit_content_filter"`
+	AfkChannelID                string `json:"afk_channel_id,omitempty"`
+	AfkTimeout                  int    `json:"afk_timeout"`
+	SystemChannelID             string `json:"system_channel_id,omitempty"`
+}
+
+// BackupRole is a role in a backup. The @everyone role has the guild's ID.
+type BackupRole struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Color       int    `json:"color"`
+	Hoist       bool   `json:"hoist"`
+	Mentionable bool   `json:"mentionable"`
+	Managed     bool   `json:"managed"`
+	Position    int    `json:"position"`
+	Permissions int64  `json:"permissions"`
+}
+
+// BackupChannel is a channel or category in a backup
+type BackupChannel struct {
+	ID               string                           `json:"id"`
+	Name             string                           `json:"name"`
+	Type             discordgo.ChannelType            `json:"type"`
+	Topic            string                           `json:"topic,omitempty"`
+	Position         int                              `json:"position"`
+	ParentID         string                           `json:"parent_id,omitempty"`
+	NSFW             bool                             `json:"nsfw"`
+	Bitrate          int                              `json:"bitrate,omitempty"`
+	UserLimit        int                              `json:"user_limit,omitempty"`
+	RateLimitPerUser int                              `json:"rate_limit_per_user,omitempty"`
+	Overwrites       []*discordgo.PermissionOverwrite `json:"overwrites"`
+}
+
+// BackupEmoji is a custom emoji in a backup. Image is a data URI.
+type BackupEmoji struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Animated bool     `json:"animated"`
+	Managed  bool     `json:"managed"`
+	Roles    []string `json:"roles,omitempty"`
+	Image    string   `json:"image"`
+}
+
+// imageDataURI downloads an image and encodes it as a data URI
+func imageDataURI(url string) (string, error) {
+	data, contentType, err := FetchImageData(url)
+	if err != nil {
+		return "", err
+	}
+	return "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// BackupGuild takes a snapshot of a guild. Threads are not included.
+// Images that can not be downloaded are left out of the backup and
+// Logged to out.
+func (c *Client) BackupGuild(guildID string, out io.Writer) (*Backup, error) {
+	guild, err := c.Cli.State.Guild(guildID)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := c.GuildRoles(guildID)
+	if err != nil {
+		return nil, err
+	}
+	channels, err := c.GuildChannels(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Backup{
+		Version:   BackupVersion,
+		CreatedAt: time.Now().UTC(),
+		Guild: &BackupGuild{
+			ID:                          guild.ID,
+			Name:                        guild.Name,
+			IconHash:                    guild.Icon,
+			VerificationLevel:           int(guild.VerificationLevel),
+			DefaultMessageNotifications: int(guild.DefaultMessageNotifications),
+			ExplicitContentFilter:       int(guild.ExplicitContentFilter),
+			AfkChannelID:                guild.AfkChannelID,
+			AfkTimeout:                  guild.AfkTimeout,
+			SystemChannelID:             guild.SystemChannelID,
+		},
+		Roles:    []*BackupRole{},
+		Channels: []*BackupChannel{},
+		Emojis:   []*BackupEmoji{},
+	}
+	if guild.Icon != "" {
+		b.Guild.Icon, err = imageDataURI(discordgo.EndpointGuildIcon(guild.ID, guild.Icon))
+		if err != nil {
+			fmt.Fprintln(out, "Could not save the guild icon:", err)
+		}
+	}
+
+	for _, r := range roles {
+		b.Roles = append(b.Roles, &BackupRole{
+			ID:          r.ID,
+			Name:        r.Name,
+			Color:       r.Color,
+			Hoist:       r.Hoist,
+			Mentionable: r.Mentionable,
+			Managed:     r.Managed,
+			Position:    r.Position,
+			Permissions: int64(r.Permissions),
+		})
+	}
+
+	sort.Slice(channels, func(i, j int) bool {
+		return channelLess(channels[i], channels[j])
+	})
+	for _, ch := range channels {
+		if IsThreadType(ch.Type) {
+			continue
+		}
+		bc := &BackupChannel{
+			ID:               ch.ID,
+			Name:             ch.Name,
+			Type:             ch.Type,
+			Topic:            ch.Topic,
+			Position:         ch.Position,
+			ParentID:         ch.ParentID,
+			NSFW:             ch.NSFW,
+			Bitrate:          ch.Bitrate,
+			UserLimit:        ch.UserLimit,
+			RateLimitPerUser: ch.RateLimitPerUser,
+			Overwrites:       []*discordgo.PermissionOverwrite{},
+		}
+		for _, o := range ch.PermissionOverwrites {
+			bc.Overwrites = append(bc.Overwrites, &discordgo.PermissionOverwrite{ID: o.ID, Type: o.Type, Allow: o.Allow, Deny: o.Deny})
+		}
+		b.Channels = append(b.Channels, bc)
+	}
+
+	for _, e := range guild.Emojis {
+		be := &BackupEmoji{
+			ID:       e.ID,
+			Name:     e.Name,
+			Animated: e.Animated,
+			Managed:  e.Managed,
+			Roles:    e.Roles,
+		}
+		url := discordgo.EndpointEmoji(e.ID)
+		if e.Animated {
+			url = discordgo.EndpointEmojiAnimated(e.ID)
+		}
+		be.Image, err = imageDataURI(url)
+		if err != nil {
+			fmt.Fprintf(out, "Could not save the emoji %s: %v\n", e.Name, err)
+			continue
+		}
+		b.Emojis = append(b.Emojis, be)
+	}
+	return b, nil
+}
+
+// Write writes a backup to a file as indented JSON
+func (b *Backup) Write(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// ReadBackup reads a backup file written by Backup.Write
+func ReadBackup(path string) (*Backup, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	b := &Backup{}
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil, fmt.Errorf("%s is not a guild backup: %v", path, err)
+	}
+	if b.Version < 1 || b.Guild == nil {
+		return nil, errors.New(path + " is not a guild backup")
+	}
+	if b.Version > BackupVersion {
+		return nil, fmt.Errorf("%s is a version %d backup, only backups up to version %d can be read", path, b.Version, BackupVersion)
+	}
+	return b, nil
+}
+
+// RestoreStep is a change that restoring a backup makes to a guild
+type RestoreStep struct {
+	Description string
+	apply       func() error
+}
+
+// RestorePlan is the list of changes needed to make a guild match a backup
+type RestorePlan struct {
+	GuildID string
+	Steps   []*RestoreStep
+
+	// Notes lists what can not be restored, such as the permission overwrites
+	// Of managed roles that the target guild does not have
+	Notes []string
+
+	// ids maps the IDs of roles and channels in the backup to their IDs in the
+	// Target guild. Roles and channels that are created are added as they are.
+	ids map[string]string
+	// creating holds the IDs of the roles in the backup that the plan creates
+	creating map[string]bool
+	// roleNames maps the IDs of the roles in the backup to their names
+	roleNames map[string]string
+}
+
+// add appends a step to the plan
+func (p *RestorePlan) add(description string, apply func() error) {
+	p.Steps = append(p.Steps, &RestoreStep{description, apply})
+}
+
+// mapID returns the ID in the target guild of a role or channel in the backup
+func (p *RestorePlan) mapID(id string) (string, bool) {
+	mapped, ok := p.ids[id]
+	return mapped, ok
+}
+
+// mapRoles returns the IDs in the target guild of roles in the backup,
+// Leaving out roles that do not exist there
+func (p *RestorePlan) mapRoles(ids []string) []string {
+	mapped := []string{}
+	for _, id := range ids {
+		if m, ok := p.mapID(id); ok {
+			mapped = append(mapped, m)
+		}
+	}
+	return mapped
+}
+
+// roleName returns the name of a role in the backup
+func (p *RestorePlan) roleName(id string) string {
+	if name, ok := p.roleNames[id]; ok {
+		return name
+	}
+	return id
+}
+
+// hasOverwrite returns true if a channel has an overwrite for a role or member with
+// The given permissions
+func hasOverwrite(ch *discordgo.Channel, id string, allow, deny int64) bool {
+	for _, o := range ch.PermissionOverwrites {
+		if o.ID == id && o.Allow == allow && o.Deny == deny {
+			return true
+		}
+	}
+	return false
+}
+
+// overwriteChanges returns the overwrites of a backup channel that a channel in the target
+// Guild does not have with the same permissions, and the names of the roles whose overwrites
+// Can not be restored because the roles will not exist in the target guild.
+// existing is nil for channels that will be created.
+func (p *RestorePlan) overwriteChanges(bc *BackupChannel, existing *discordgo.Channel) (changed []*discordgo.PermissionOverwrite, dropped []string) {
+	for _, o := range bc.Overwrites {
+		id := o.ID
+		if o.Type == discordgo.PermissionOverwriteTypeRole {
+			mapped, ok := p.mapID(o.ID)
+			if !ok && !p.creating[o.ID] {
+				dropped = append(dropped, p.roleName(o.ID))
+				continue
+			}
+			// Overwrites of roles that are being created always change
+			id = mapped
+		}
+		if existing != nil && id != "" && hasOverwrite(existing, id, o.Allow, o.Deny) {
+			continue
+		}
+		changed = append(changed, o)
+	}
+	return changed, dropped
+}
+
+// mapOverwrites returns overwrites from the backup with their role IDs mapped to the target
+// Guild, and the names of the roles that do not exist there because creating them failed
+func (p *RestorePlan) mapOverwrites(overwrites []*discordgo.PermissionOverwrite) (mapped []*discordgo.PermissionOverwrite, missing []string) {
+	mapped = []*discordgo.PermissionOverwrite{}
+	for _, o := range overwrites {
+		id := o.ID
+		if o.Type == discordgo.PermissionOverwriteTypeRole {
+			var ok bool
+			if id, ok = p.mapID(o.ID); !ok {
+				missing = append(missing, p.roleName(o.ID))
+				continue
+			}
+		}
+		mapped = append(mapped, &discordgo.PermissionOverwrite{ID: id, Type: o.Type, Allow: o.Allow, Deny: o.Deny})
+	}
+	return mapped, missing
+}
+
+// missingRolesError returns an error naming the roles whose overwrites were not restored
+func missingRolesError(missing []string) error {
+	if len(missing) == 0 {
+		return nil
+	}
+	return errors.New("the overwrites for " + strings.Join(missing, ", ") + " were not restored because the roles do not exist")
+}
+
+// PlanRestore compares a backup with a guild and plans the changes that recreate
+// What is missing and update what differs. Nothing is changed until the plan
+// Is applied. Roles, channels and emojis are matched by name, and channels by
+// Their type and category as well. Nothing is deleted from the target guild: permission
+// Overwrites are added or changed one at a time. Managed roles and emojis, which belong
+// To integrations, are not created.
+func (c *Client) PlanRestore(b *Backup, guildID string) (*RestorePlan, error) {
+	guild, err := c.Cli.State.Guild(guildID)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := c.GuildRoles(guildID)
+	if err != nil {
+		return nil, err
+	}
+	channels, err := c.GuildChannels(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &RestorePlan{
+		GuildID:   guildID,
+		ids:       map[string]string{b.Guild.ID: guildID},
+		creating:  map[string]bool{},
+		roleNames: map[string]string{},
+	}
+	for _, r := range b.Roles {
+		p.roleNames[r.ID] = r.Name
+	}
+	c.planRoles(p, b, roles)
+	c.planChannels(p, b, channels)
+	c.planEmojis(p, b, guild)
+	c.planGuild(p, b, guild)
+	return p, nil
+}
+
+// planRoles plans the creation, update and ordering of roles
+func (c *Client) planRoles(p *RestorePlan, b *Backup, roles []*discordgo.Role) {
+	used := map[string]bool{}
+	var everyone *discordgo.Role
+	for _, r := range roles {
+		if r.ID == p.GuildID {
+			everyone = r
+		}
+	}
+	find := func(name string) *discordgo.Role {
+		for _, r := range roles {
+			if r.Name == name && !r.Managed && r.ID != p.GuildID && !used[r.ID] {
+				used[r.ID] = true
+				return r
+			}
+		}
+		return nil
+	}
+
+	changed := false
+	for _, br := range b.Roles {
+		br := br
+		if br.Managed {
+			// Roles of integrations can not be created, but their overwrites
+			// Can be restored if the integration is in the target guild
+			for _, r := range roles {
+				if r.Managed && r.Name == br.Name && !used[r.ID] {
+					used[r.ID] = true
+					p.ids[br.ID] = r.ID
+					break
+				}
+			}
+			continue
+		}
+		params := &discordgo.RoleParams{
+			Name:        br.Name,
+			Color:       &br.Color,
+			Hoist:       &br.Hoist,
+			Mentionable: &br.Mentionable,
+			Permissions: &br.Permissions,
+		}
+
+		var existing *discordgo.Role
+		if br.ID == b.Guild.ID {
+			existing = everyone
+			// @everyone can only have its permissions changed
+			params = &discordgo.RoleParams{Permissions: &br.Permissions}
+		} else {
+			existing = find(br.Name)
+		}
+		if existing == nil {
+			changed = true
+			p.creating[br.ID] = true
+			p.add("create role "+br.Name, func() error {
+				role, err := c.CreateRole(p.GuildID, params)
+				if err != nil {
+					return err
+				}
+				p.ids[br.ID] = role.ID
+				return nil
+			})
+			continue
+		}
+
+		p.ids[br.ID] = existing.ID
+		if existing.Position != br.Position && br.ID != b.Guild.ID {
+			changed = true
+		}
+		diff := []string{}
+		if existing.Color != br.Color && params.Color != nil {
+			diff = append(diff, "color")
+		}
+		if existing.Hoist != br.Hoist && params.Hoist != nil {
+			diff = append(diff, "hoist")
+		}
+		if existing.Mentionable != br.Mentionable && params.Mentionable != nil {
+			diff = append(diff, "mentionable")
+		}
+		if int64(existing.Permissions) != br.Permissions {
+			diff = append(diff, "permissions")
+		}
+		if len(diff) > 0 {
+			id := existing.ID
+			p.add("update role "+br.Name+" ("+strings.Join(diff, ", ")+")", func() error {
+				_, err := c.EditRole(p.GuildID, id, params)
+				return err
+			})
+		}
+	}
+
+	if !changed {
+		return
+	}
+	p.add("reorder roles", func() error {
+		// Only the IDs and positions of the roles are read
+		positions := []*discordgo.Role{}
+		for _, br := range b.Roles {
+			if id, ok := p.mapID(br.ID); ok && !br.Managed && br.ID != b.Guild.ID {
+				positions = append(positions, &discordgo.Role{ID: id, Position: br.Position})
+			}
+		}
+		updated, err := c.Cli.GuildRoleReorder(p.GuildID, positions)
+		if err != nil {
+			return err
+		}
+		for _, r := range updated {
+			c.Cli.State.RoleAdd(p.GuildID, r)
+		}
+		return nil
+	})
+}
+
+// planChannels plans the creation, update and ordering of categories and channels
+func (c *Client) planChannels(p *RestorePlan, b *Backup, channels []*discordgo.Channel) {
+	used := map[string]bool{}
+	find := func(bc *BackupChannel) *discordgo.Channel {
+		parentID := ""
+		if bc.ParentID != "" {
+			var ok bool
+			// Channels of a category that is being created are created as well
+			if parentID, ok = p.mapID(bc.ParentID); !ok {
+				return nil
+			}
+		}
+		for _, ch := range channels {
+			if ch.Name == bc.Name && ch.Type == bc.Type && ch.ParentID == parentID && !used[ch.ID] {
+				used[ch.ID] = true
+				return ch
+			}
+		}
+		return nil
+	}
+
+	// Categories are planned first so that their channels can be matched
+	ordered := []*BackupChannel{}
+	for _, bc := range b.Channels {
+		if bc.Type == discordgo.ChannelTypeGuildCategory {
+			ordered = append(ordered, bc)
+		}
+	}
+	for _, bc := range b.Channels {
+		if bc.Type != discordgo.ChannelTypeGuildCategory {
+			ordered = append(ordered, bc)
+		}
+	}
+
+	changed := false
+	for _, bc := range ordered {
+		bc := bc
+		name := ChannelTypeGlyph(bc.Type) + bc.Name
+		existing := find(bc)
+		overwrites, dropped := p.overwriteChanges(bc, existing)
+		if len(dropped) > 0 {
+			p.Notes = append(p.Notes, "the overwrites of "+name+" for "+strings.Join(dropped, ", ")+
+				" can not be restored because the roles are not in the guild and can not be created")
+		}
+		if existing == nil {
+			changed = true
+			p.add("create channel "+name, func() error {
+				data := discordgo.GuildChannelCreateData{
+					Name:             bc.Name,
+					Type:             bc.Type,
+					Topic:            bc.Topic,
+					NSFW:             bc.NSFW,
+					RateLimitPerUser: bc.RateLimitPerUser,
+				}
+				// The category may have been created by an earlier step
+				if parentID, ok := p.mapID(bc.ParentID); ok && bc.ParentID != "" {
+					data.ParentID = parentID
+				}
+				if IsVoiceType(bc.Type) {
+					data.Bitrate = bc.Bitrate
+					data.UserLimit = bc.UserLimit
+				}
+				var missing []string
+				data.PermissionOverwrites, missing = p.mapOverwrites(overwrites)
+				ch, err := c.CreateChannel(p.GuildID, data)
+				if err != nil {
+					return err
+				}
+				p.ids[bc.ID] = ch.ID
+				return missingRolesError(missing)
+			})
+			continue
+		}
+
+		p.ids[bc.ID] = existing.ID
+		if existing.Position != bc.Position {
+			changed = true
+		}
+		diff := []string{}
+		if existing.Topic != bc.Topic {
+			diff = append(diff, "topic")
+		}
+		if existing.NSFW != bc.NSFW {
+			diff = append(diff, "nsfw")
+		}
+		if existing.RateLimitPerUser != bc.RateLimitPerUser {
+			diff = append(diff, "slowmode")
+		}
+		if IsVoiceType(bc.Type) && (existing.Bitrate != bc.Bitrate || existing.UserLimit != bc.UserLimit) {
+			diff = append(diff, "bitrate and user limit")
+		}
+		editFields := len(diff) > 0
+		if len(overwrites) > 0 {
+			diff = append(diff, "permission overwrites")
+		}
+		if len(diff) > 0 {
+			id := existing.ID
+			p.add("update channel "+name+" ("+strings.Join(diff, ", ")+")", func() error {
+				if editFields {
+					data := &discordgo.ChannelEdit{
+						Topic:            bc.Topic,
+						NSFW:             &bc.NSFW,
+						RateLimitPerUser: &bc.RateLimitPerUser,
+					}
+					if IsVoiceType(bc.Type) {
+						data.Bitrate = bc.Bitrate
+						data.UserLimit = bc.UserLimit
+					}
+					if _, err := c.EditChannel(id, data); err != nil {
+						return err
+					}
+					if bc.Topic == "" && existing.Topic != "" {
+						if _, err := c.RemoveTopic(id); err != nil {
+							return err
+						}
+					}
+				}
+				// Overwrites are set one at a time so that the channel's other overwrites are kept
+				mapped, missing := p.mapOverwrites(overwrites)
+				for _, o := range mapped {
+					if err := c.Cli.ChannelPermissionSet(id, o.ID, o.Type, o.Allow, o.Deny); err != nil {
+						return err
+					}
+				}
+				return missingRolesError(missing)
+			})
+		}
+	}
+
+	if !changed {
+		return
+	}
+	p.add("reorder channels", func() error {
+		positions := []*discordgo.Channel{}
+		for _, bc := range b.Channels {
+			if id, ok := p.mapID(bc.ID); ok {
+				positions = append(positions, &discordgo.Channel{ID: id, Position: bc.Position})
+			}
+		}
+		return c.Cli.GuildChannelsReorder(p.GuildID, positions)
+	})
+}
+
+// planEmojis plans the creation of emojis that the guild does not have
+func (c *Client) planEmojis(p *RestorePlan, b *Backup, guild *discordgo.Guild) {
+	for _, be := range b.Emojis {
+		be := be
+		if be.Managed {
+			continue
+		}
+		found := false
+		for _, e := range guild.Emojis {
+			if e.Name == be.Name {
+				found = true
+			}
+		}
+		if found {
+			continue
+		}
+		p.add("create emoji :"+be.Name+":", func() error {
+			emoji, err := c.Cli.GuildEmojiCreate(p.GuildID, &discordgo.EmojiParams{
+				Name:  be.Name,
+				Image: be.Image,
+				Roles: p.mapRoles(be.Roles),
+			})
+			if err != nil {
+				return err
+			}
+			c.Cli.State.EmojiAdd(p.GuildID, emoji)
+			return nil
+		})
+	}
+}
+
+// planGuild plans the update of the guild's settings
+func (c *Client) planGuild(p *RestorePlan, b *Backup, guild *discordgo.Guild) {
+	bg := b.Guild
+	diff := []string{}
+	if guild.Name != bg.Name {
+		diff = append(diff, "name")
+	}
+	if guild.Icon != bg.IconHash && bg.Icon != "" {
+		diff = append(diff, "icon")
+	}
+	if int(guild.VerificationLevel) != bg.VerificationLevel {
+		diff = append(diff, "verification level")
+	}
+	if int(guild.DefaultMessageNotifications) != bg.DefaultMessageNotifications {
+		diff = append(diff, "default notifications")
+	}
+	if int(guild.ExplicitContentFilter) != bg.ExplicitContentFilter {
+		diff = append(diff, "content filter")
+	}
+	if guild.AfkTimeout != bg.AfkTimeout {
+		diff = append(diff, "afk timeout")
+	}
+	// The afk and system channels may not have been created yet
+	if id, ok := p.mapID(bg.AfkChannelID); guild.AfkChannelID != id || (bg.AfkChannelID != "" && !ok) {
+		diff = append(diff, "afk channel")
+	}
+	if id, ok := p.mapID(bg.SystemChannelID); guild.SystemChannelID != id || (bg.SystemChannelID != "" && !ok) {
+		diff = append(diff, "system channel")
+	}
+	if len(diff) == 0 {
+		return
+	}
+
+	p.add("update guild settings ("+strings.Join(diff, ", ")+")", func() error {
+		level := discordgo.VerificationLevel(bg.VerificationLevel)
+		params := &discordgo.GuildParams{
+			Name:                        bg.Name,
+			VerificationLevel:           &level,
+			DefaultMessageNotifications: bg.DefaultMessageNotifications,
+			ExplicitContentFilter:       bg.ExplicitContentFilter,
+			AfkTimeout:                  bg.AfkTimeout,
+		}
+		if guild.Icon != bg.IconHash && bg.Icon != "" {
+			params.Icon = bg.Icon
+		}
+		if id, ok := p.mapID(bg.AfkChannelID); ok && bg.AfkChannelID != "" {
+			params.AfkChannelID = id
+		}
+		if id, ok := p.mapID(bg.SystemChannelID); ok && bg.SystemChannelID != "" {
+			params.SystemChannelID = id
+		}
+		if _, err := c.Cli.GuildEdit(p.GuildID, params); err != nil {
+			return err
+		}
+
+		// GuildParams leaves out zero values, so the settings that are restored to
+		// Zero or to no channel are sent in a request of their own
+		reset := map[string]interface{}{}
+		if bg.DefaultMessageNotifications == 0 && guild.DefaultMessageNotifications != 0 {
+			reset["default_message_notifications"] = 0
+		}
+		if bg.ExplicitContentFilter == 0 && guild.ExplicitContentFilter != 0 {
+			reset["explicit_content_filter"] = 0
+		}
+		if bg.AfkChannelID == "" && guild.AfkChannelID != "" {
+			reset["afk_channel_id"] = nil
+		}
+		if bg.SystemChannelID == "" && guild.SystemChannelID != "" {
+			reset["system_channel_id"] = nil
+		}
+		if len(reset) == 0 {
+			return nil
+		}
+		endpoint := discordgo.EndpointGuild(p.GuildID)
+		_, err := c.Cli.RequestWithBucketID("PATCH", endpoint, reset, endpoint)
+		return err
+	})
+}
+
+// ApplyRestore makes the changes of a restore plan in order, drawing progress to out.
+// Steps that fail do not stop the restore, their errors are returned.
+func (c *Client) ApplyRestore(p *RestorePlan, out io.Writer) (failures []error) {
+	bar := NewProgressBar(out, "changes", int64(len(p.Steps)))
+	bar.Count = true
+	bar.Draw()
+	defer c.rateLimitProgress(bar)()
+
+	for _, step := range p.Steps {
+		if err := step.apply(); err != nil {
+			failures = append(failures, fmt.Errorf("%s: %v", step.Description, err))
+		}
+		bar.Add(1)
+	}
+	bar.Finish()
+	return failures
+}
@@ -0,0 +1,177 @@
+package discordterm
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// fakeRequest is a request made to the fake API
+type fakeRequest struct {
+	Method string
+	Path   string
+	Body   map[string]json.RawMessage
+}
+
+// fakeAPI answers the requests that applying a restore plan makes
+// And records them
+type fakeAPI struct {
+	requests []*fakeRequest
+}
+
+func (f *fakeAPI) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := &fakeRequest{
+		Method: req.Method,
+		Path:   req.URL.Path[strings.Index(req.URL.Path, "/v"+discordgo.APIVersion)+len(discordgo.APIVersion)+2:],
+	}
+	if req.Body != nil {
+		data, _ := ioutil.ReadAll(req.Body)
+		json.Unmarshal(data, &r.Body)
+	}
+	f.requests = append(f.requests, r)
+
+	var name string
+	json.Unmarshal(r.Body["name"], &name)
+	body := ""
+	switch {
+	case r.Method == "POST" && r.Path == "/guilds/g/roles":
+		body = `{"id":"new-` + name + `","name":"` + name + `"}`
+	case r.Method == "POST" && r.Path == "/guilds/g/channels":
+		body = `{"id":"new-` + name + `","name":"` + name + `","guild_id":"g","type":` + string(r.Body["type"]) + `}`
+	case r.Method == "PATCH" && r.Path == "/guilds/g/roles":
+		body = `[]`
+	}
+	status := http.StatusOK
+	if body == "" {
+		status = http.StatusNoContent
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// find returns the first request with a method and path
+func (f *fakeAPI) find(method, path string) *fakeRequest {
+	for _, r := range f.requests {
+		if r.Method == method && r.Path == path {
+			return r
+		}
+	}
+	return nil
+}
+
+func TestPlanRestore(t *testing.T) {
+	s, err := discordgo.New("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := &fakeAPI{}
+	s.Client = &http.Client{Transport: api}
+	c := NewClient(s, nil)
+
+	s.State.GuildAdd(&discordgo.Guild{
+		ID:   "g",
+		Name: "Guild",
+		Roles: []*discordgo.Role{
+			{ID: "g", Name: "@everyone"},
+			{ID: "t-bot", Name: "Bot", Managed: true, Position: 1},
+		},
+		Channels: []*discordgo.Channel{
+			{ID: "t-general", GuildID: "g", Name: "general", Type: discordgo.ChannelTypeGuildText, PermissionOverwrites: []*discordgo.PermissionOverwrite{
+				{ID: "g", Type: discordgo.PermissionOverwriteTypeRole, Deny: 1 << 11},
+				{ID: "u", Type: discordgo.PermissionOverwriteTypeMember, Allow: 1 << 11},
+			}},
+		},
+	})
+
+	b := &Backup{
+		Guild: &BackupGuild{ID: "b", Name: "Guild"},
+		Roles: []*BackupRole{
+			{ID: "b", Name: "@everyone"},
+			{ID: "b-mods", Name: "Mods", Position: 2},
+			{ID: "b-bot", Name: "Bot", Managed: true, Position: 1},
+			{ID: "b-gone", Name: "Gone", Managed: true, Position: 1},
+		},
+		Channels: []*BackupChannel{
+			{ID: "b-chat", Name: "chat", Type: discordgo.ChannelTypeGuildText, ParentID: "b-staff", Overwrites: []*discordgo.PermissionOverwrite{
+				{ID: "b-mods", Type: discordgo.PermissionOverwriteTypeRole, Allow: 1 << 11},
+				{ID: "b-gone", Type: discordgo.PermissionOverwriteTypeRole, Allow: 1 << 11},
+			}},
+			{ID: "b-staff", Name: "Staff", Type: discordgo.ChannelTypeGuildCategory},
+			{ID: "b-general", Name: "general", Type: discordgo.ChannelTypeGuildText, Overwrites: []*discordgo.PermissionOverwrite{
+				{ID: "b", Type: discordgo.PermissionOverwriteTypeRole, Deny: 1 << 11},
+				{ID: "b-bot", Type: discordgo.PermissionOverwriteTypeRole, Allow: 1 << 13},
+			}},
+		},
+	}
+
+	p, err := c.PlanRestore(b, "g")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []string{}
+	for _, step := range p.Steps {
+		steps = append(steps, step.Description)
+	}
+	want := []string{
+		"create role Mods",
+		"reorder roles",
+		"create channel " + ChannelTypeGlyph(discordgo.ChannelTypeGuildCategory) + "Staff",
+		"create channel " + ChannelTypeGlyph(discordgo.ChannelTypeGuildText) + "chat",
+		"update channel " + ChannelTypeGlyph(discordgo.ChannelTypeGuildText) + "general (permission overwrites)",
+		"reorder channels",
+	}
+	if !reflect.DeepEqual(steps, want) {
+		t.Fatalf("steps = %q, want %q", steps, want)
+	}
+	if len(p.Notes) != 1 || !strings.Contains(p.Notes[0], "chat for Gone") {
+		t.Errorf("notes = %q, want a note about the overwrites of chat for Gone", p.Notes)
+	}
+
+	if failures := c.ApplyRestore(p, ioutil.Discard); len(failures) > 0 {
+		t.Fatalf("ApplyRestore failed: %v", failures)
+	}
+
+	// The channel is created in the category created before it,
+	// With the overwrites of the role created before that
+	var chat *fakeRequest
+	for _, r := range api.requests {
+		if r.Method == "POST" && bytes.Equal(r.Body["name"], []byte(`"chat"`)) {
+			chat = r
+		}
+	}
+	if chat == nil {
+		t.Fatal("chat was not created")
+	}
+	var parentID string
+	json.Unmarshal(chat.Body["parent_id"], &parentID)
+	if parentID != "new-Staff" {
+		t.Errorf("chat was created in %q, want new-Staff", parentID)
+	}
+	var overwrites []*discordgo.PermissionOverwrite
+	json.Unmarshal(chat.Body["permission_overwrites"], &overwrites)
+	if len(overwrites) != 1 || overwrites[0].ID != "new-Mods" || overwrites[0].Allow != 1<<11 {
+		t.Errorf("chat was created with overwrites %s, want one for new-Mods", chat.Body["permission_overwrites"])
+	}
+
+	// Only the overwrite that differs is set, the channel itself is not edited
+	if r := api.find("PATCH", "/channels/t-general"); r != nil {
+		t.Errorf("general was edited although only its overwrites differ")
+	}
+	if r := api.find("PUT", "/channels/t-general/permissions/t-bot"); r == nil {
+		t.Errorf("the overwrite of the Bot role was not set on general")
+	}
+	if r := api.find("PUT", "/channels/t-general/permissions/g"); r != nil {
+		t.Errorf("the unchanged overwrite of @everyone was set on general")
+	}
+}
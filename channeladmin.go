@@ -0,0 +1,141 @@
+package discordterm
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// MaxSlowmode is the longest slowmode discord allows, in seconds
+const MaxSlowmode = 21600
+
+// creatableChannelTypes are the channel types that can be created by name
+var creatableChannelTypes = []discordgo.ChannelType{
+	discordgo.ChannelTypeGuildText,
+	discordgo.ChannelTypeGuildVoice,
+	discordgo.ChannelTypeGuildCategory,
+	discordgo.ChannelTypeGuildNews,
+	discordgo.ChannelTypeGuildStageVoice,
+	discordgo.ChannelTypeGuildForum,
+}
+
+// ParseChannelType returns the channel type with the given name, as
+// Returned by ChannelTypeName. "news" may be used for announcement channels.
+func ParseChannelType(name string) (discordgo.ChannelType, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "news" {
+		return discordgo.ChannelTypeGuildNews, nil
+	}
+	for _, t := range creatableChannelTypes {
+		if ChannelTypeName(t) == name {
+			return t, nil
+		}
+	}
+	return 0, errors.New("unknown channel type: " + name)
+}
+
+// CreateChannel creates a channel in a guild
+func (c *Client) CreateChannel(guildID string, data discordgo.GuildChannelCreateData) (*discordgo.Channel, error) {
+	channel, err := c.Cli.GuildChannelCreateComplex(guildID, data)
+	if err != nil {
+		return nil, err
+	}
+	c.Cli.State.ChannelAdd(channel)
+	return channel, nil
+}
+
+// EditChannel changes the fields of a channel that are set in data
+func (c *Client) EditChannel(channelID string, data *discordgo.ChannelEdit) (*discordgo.Channel, error) {
+	channel, err := c.Cli.ChannelEdit(channelID, data)
+	if err != nil {
+		return nil, err
+	}
+	c.Cli.State.ChannelAdd(channel)
+	return channel, nil
+}
+
+// RemoveTopic removes the topic of a channel. ChannelEdit leaves out empty topics,
+// So the topic is set to null with a request of its own.
+func (c *Client) RemoveTopic(channelID string) (*discordgo.Channel, error) {
+	endpoint := discordgo.EndpointChannel(channelID)
+	body, err := c.Cli.RequestWithBucketID("PATCH", endpoint, map[string]interface{}{"topic": nil}, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	channel := &discordgo.Channel{}
+	if err := json.Unmarshal(body, channel); err != nil {
+		return nil, err
+	}
+	c.Cli.State.ChannelAdd(channel)
+	return channel, nil
+}
+
+// DeleteChannel deletes a channel
+func (c *Client) DeleteChannel(channelID string) error {
+	channel, err := c.Cli.ChannelDelete(channelID)
+	if err != nil {
+		return err
+	}
+	c.Cli.State.ChannelRemove(channel)
+	return nil
+}
+
+// ResolveOverwriteTarget finds the role or member a permission overwrite applies to.
+// Roles are matched before members.
+func (c *Client) ResolveOverwriteTarget(guildID, query string) (id string, targetType discordgo.PermissionOverwriteType, name string, err error) {
+	if role, err := c.ResolveRole(guildID, query); err == nil {
+		return role.ID, discordgo.PermissionOverwriteTypeRole, "role " + role.Name, nil
+	}
+	member, err := c.ResolveMember(guildID, query)
+	if err != nil {
+		return "", 0, "", errors.New("no role or member named " + query + " was found")
+	}
+	return member.User.ID, discordgo.PermissionOverwriteTypeMember, "member " + member.User.Username, nil
+}
+
+// UpdateOverwrite changes the permission overwrite of a role or member in a channel.
+// Permissions in allow are allowed, permissions in deny are denied and permissions
+// In inherit are removed from the overwrite. The overwrite is deleted when it no longer
+// Allows or denies anything.
+func (c *Client) UpdateOverwrite(channelID, targetID string, targetType discordgo.PermissionOverwriteType, allow, deny, inherit int64) (*discordgo.PermissionOverwrite, error) {
+	channel, err := c.Channel(channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	var curAllow, curDeny int64
+	for _, o := range channel.PermissionOverwrites {
+		if o.ID == targetID {
+			curAllow, curDeny = int64(o.Allow), int64(o.Deny)
+		}
+	}
+	curAllow = (curAllow | allow) &^ (deny | inherit)
+	curDeny = (curDeny | deny) &^ (allow | inherit)
+
+	if curAllow == 0 && curDeny == 0 {
+		err = c.Cli.ChannelPermissionDelete(channelID, targetID)
+	} else {
+		err = c.Cli.ChannelPermissionSet(channelID, targetID, targetType, curAllow, curDeny)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the state up to date until the channel update event arrives
+	o := &discordgo.PermissionOverwrite{ID: targetID, Type: targetType, Allow: curAllow, Deny: curDeny}
+	c.Cli.State.Lock()
+	overwrites := []*discordgo.PermissionOverwrite{}
+	for _, existing := range channel.PermissionOverwrites {
+		if existing.ID != targetID {
+			overwrites = append(overwrites, existing)
+		}
+	}
+	if curAllow != 0 || curDeny != 0 {
+		overwrites = append(overwrites, o)
+	}
+	channel.PermissionOverwrites = overwrites
+	c.Cli.State.Unlock()
+	return o, nil
+}
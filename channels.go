@@ -0,0 +1,230 @@
+package discordterm
+
+import (
+	"sort"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ChannelTypeName returns a readable name for a channel type
+func ChannelTypeName(t discordgo.ChannelType) string {
+	switch t {
+	case discordgo.ChannelTypeGuildText:
+		return "text"
+	case discordgo.ChannelTypeDM:
+		return "direct message"
+	case discordgo.ChannelTypeGuildVoice:
+		return "voice"
+	case discordgo.ChannelTypeGroupDM:
+		return "group direct message"
+	case discordgo.ChannelTypeGuildCategory:
+		return "category"
+	case discordgo.ChannelTypeGuildNews:
+		return "announcement"
+	case discordgo.ChannelTypeGuildStore:
+		return "store"
+	case discordgo.ChannelTypeGuildNewsThread:
+		return "announcement thread"
+	case discordgo.ChannelTypeGuildPublicThread:
+		return "thread"
+	case discordgo.ChannelTypeGuildPrivateThread:
+		return "private thread"
+	case discordgo.ChannelTypeGuildStageVoice:
+		return "stage"
+	case discordgo.ChannelTypeGuildDirectory:
+		return "directory"
+	case discordgo.ChannelTypeGuildForum:
+		return "forum"
+	case discordgo.ChannelTypeGuildMedia:
+		return "media"
+	default:
+		return "unknown"
+	}
+}
+
+// ChannelTypeGlyph returns a short symbol for a channel type
+func ChannelTypeGlyph(t discordgo.ChannelType) string {
+	switch t {
+	case discordgo.ChannelTypeGuildText:
+		return "#"
+	case discordgo.ChannelTypeGuildVoice:
+		return "♪"
+	case discordgo.ChannelTypeGuildCategory:
+		return "▾"
+	case discordgo.ChannelTypeGuildNews:
+		return "!"
+	case discordgo.ChannelTypeGuildStore:
+		return "$"
+	case discordgo.ChannelTypeGuildNewsThread, discordgo.ChannelTypeGuildPublicThread, discordgo.ChannelTypeGuildPrivateThread:
+		return "»"
+	case discordgo.ChannelTypeGuildStageVoice:
+		return "◉"
+	case discordgo.ChannelTypeGuildForum, discordgo.ChannelTypeGuildMedia:
+		return "≡"
+	default:
+		return "?"
+	}
+}
+
+// IsVoiceType returns true for channel types that are listed
+// After text channels in the discord client
+func IsVoiceType(t discordgo.ChannelType) bool {
+	return t == discordgo.ChannelTypeGuildVoice || t == discordgo.ChannelTypeGuildStageVoice
+}
+
+// ChannelTreeEntry is a channel in a guild's channel tree
+type ChannelTreeEntry struct {
+	Channel *discordgo.Channel
+
+	// Depth is 0 for categories and channels outside of a category,
+	// 1 for channels inside a category and one more than their channel for threads
+	Depth int
+
+	// Index is the index used to select the channel, or -1 for categories
+	Index int
+
+	// Hidden is true if the client's user can not view the channel
+	Hidden bool
+}
+
+// channelLess orders channels the way the discord client does: text like
+// Channels before voice channels, then by position, then by ID
+func channelLess(a, b *discordgo.Channel) bool {
+	if IsVoiceType(a.Type) != IsVoiceType(b.Type) {
+		return !IsVoiceType(a.Type)
+	}
+	if a.Position != b.Position {
+		return a.Position < b.Position
+	}
+	if len(a.ID) != len(b.ID) {
+		return len(a.ID) < len(b.ID)
+	}
+	return a.ID < b.ID
+}
+
+// GuildChannels returns the channels and known threads of a guild,
+// From the state when possible
+func (c *Client) GuildChannels(guildID string) ([]*discordgo.Channel, error) {
+	if guild, err := c.Cli.State.Guild(guildID); err == nil && len(guild.Channels) > 0 {
+		c.Cli.State.RLock()
+		channels := make([]*discordgo.Channel, 0, len(guild.Channels)+len(guild.Threads))
+		channels = append(channels, guild.Channels...)
+		channels = append(channels, guild.Threads...)
+		c.Cli.State.RUnlock()
+		return channels, nil
+	}
+	return c.Cli.GuildChannels(guildID)
+}
+
+// ChannelTree returns a guild's channels grouped under their categories
+// In the order the discord client displays them. Channels outside of a
+// Category come first. Every channel except categories is given an
+// Index in display order.
+func (c *Client) ChannelTree(guildID string) ([]*ChannelTreeEntry, error) {
+	channels, err := c.GuildChannels(guildID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(channels, func(i, j int) bool {
+		return channelLess(channels[i], channels[j])
+	})
+
+	categories := []*discordgo.Channel{}
+	children := map[string][]*discordgo.Channel{}
+	threads := map[string][]*discordgo.Channel{}
+	for _, ch := range channels {
+		if IsThreadType(ch.Type) {
+			// Archived threads are only listed by /threads
+			if !(&Thread{ch}).Archived() {
+				threads[ch.ParentID] = append(threads[ch.ParentID], ch)
+			}
+		} else if ch.Type == discordgo.ChannelTypeGuildCategory {
+			categories = append(categories, ch)
+		} else {
+			children[ch.ParentID] = append(children[ch.ParentID], ch)
+		}
+	}
+
+	// Channels whose category no longer exists are listed with the uncategorized channels
+	uncategorized := children[""]
+	for parentID, chs := range children {
+		found := false
+		for _, cat := range categories {
+			if cat.ID == parentID {
+				found = true
+			}
+		}
+		if parentID != "" && !found {
+			uncategorized = append(uncategorized, chs...)
+		}
+	}
+	sort.SliceStable(uncategorized, func(i, j int) bool {
+		return channelLess(uncategorized[i], uncategorized[j])
+	})
+
+	// Channels the user can not view are left out unless ShowHiddenChannels is set
+	visible := func(ch *discordgo.Channel) bool {
+		return c.Conf.ShowHiddenChannels || c.CanViewChannel(ch)
+	}
+
+	tree := []*ChannelTreeEntry{}
+	index := 0
+	add := func(ch *discordgo.Channel, depth int) {
+		e := &ChannelTreeEntry{Channel: ch, Depth: depth, Index: -1}
+		if ch.Type != discordgo.ChannelTypeGuildCategory {
+			e.Index = index
+			index++
+		}
+		e.Hidden = !c.CanViewChannel(ch)
+		tree = append(tree, e)
+	}
+
+	// Active threads are listed below their channel
+	addWithThreads := func(ch *discordgo.Channel, depth int) {
+		add(ch, depth)
+		for _, t := range threads[ch.ID] {
+			if visible(t) {
+				add(t, depth+1)
+			}
+		}
+	}
+
+	for _, ch := range uncategorized {
+		if visible(ch) {
+			addWithThreads(ch, 0)
+		}
+	}
+	for _, cat := range categories {
+		shown := []*discordgo.Channel{}
+		for _, ch := range children[cat.ID] {
+			if visible(ch) {
+				shown = append(shown, ch)
+			}
+		}
+		// Empty categories are only listed if they can be viewed
+		if len(shown) == 0 && !visible(cat) {
+			continue
+		}
+		add(cat, 0)
+		for _, ch := range shown {
+			addWithThreads(ch, 1)
+		}
+	}
+	return tree, nil
+}
+
+// SelectableChannels returns the channels of a guild that can be selected,
+// Ordered by their index in the channel tree
+func (c *Client) SelectableChannels(guildID string) ([]*discordgo.Channel, error) {
+	tree, err := c.ChannelTree(guildID)
+	if err != nil {
+		return nil, err
+	}
+	channels := []*discordgo.Channel{}
+	for _, e := range tree {
+		if e.Index >= 0 {
+			channels = append(channels, e.Channel)
+		}
+	}
+	return channels, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// errEmptyMessage is returned when the user saves an empty file in their editor
+var errEmptyMessage = errors.New("Message is empty, nothing was sent")
+
+// errCancelled is returned when the user cancels a paragraph
+var errCancelled = errors.New("Cancelled, nothing was sent")
+
+// editorCommand returns the user's preferred editor from $VISUAL or $EDITOR
+func editorCommand() string {
+	if e := os.Getenv("VISUAL"); e != "" {
+		return e
+	}
+	return os.Getenv("EDITOR")
+}
+
+// hasEditor returns true if the user has configured an editor
+func hasEditor() bool {
+	return editorCommand() != ""
+}
+
+// composeInEditor opens the user's editor on a temporary file containing
+// Initial, and returns the contents of the file once the editor exits.
+// errEmptyMessage is returned if the file is empty.
+func composeInEditor(initial string) (string, error) {
+	editor := strings.Fields(editorCommand())
+	if len(editor) == 0 {
+		return "", errors.New("Set $VISUAL or $EDITOR to compose messages in an editor")
+	}
+
+	f, err := ioutil.TempFile("", "discordterm-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString(initial)
+	f.Close()
+	if err != nil {
+		return "", err
+	}
+
+	cmd := exec.Command(editor[0], append(editor[1:], f.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+
+	data, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	content := strings.TrimRight(string(data), " \t\r\n")
+	if strings.TrimSpace(content) == "" {
+		return "", errEmptyMessage
+	}
+	return content, nil
+}
+
+// composeParagraph reads a multi-line message starting with initial, in the
+// User's editor if they have one. Otherwise lines are read until /send is typed.
+// errCancelled is returned if the user types /cancel.
+func composeParagraph(initial string) (string, error) {
+	if hasEditor() {
+		return composeInEditor(initial)
+	}
+
+	lines := []string{}
+	if initial != "" {
+		lines = append(lines, initial)
+	}
+
+	rd := bufio.NewReader(os.Stdin)
+
+	fmt.Println("Enter your paragraph in multiple lines. Type /send or /cancel to finish")
+	for {
+		line := strings.Trim(ReadInputString(rd), "\r\n")
+		switch line {
+		case "/send":
+			return strings.Join(lines, "\n"), nil
+		case "/cancel":
+			return "", errCancelled
+		default:
+			lines = append(lines, line)
+		}
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
@@ -13,6 +15,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/chzyer/readline"
@@ -37,6 +40,13 @@ var (
 	imageWidth    = app.Flag("img-width", "Sets the default width of images").Default("100").Uint()
 	colorImages   = app.Flag("color-images", "If enabled, images will have color").Bool()
 	colorText     = app.Flag("color-text", "If enabled, Text will be colored").Short('c').Default("true").Bool()
+	autoPlay      = app.Flag("auto-play", "If enabled, animated images in message history will be played").Bool()
+	imageLoops    = app.Flag("img-loops", "The maximum number of times to loop an animation").Default("3").Int()
+	showEdits     = app.Flag("show-edits", "Show edits to messages in the active channel").Default("true").Bool()
+	showDeletes   = app.Flag("show-deletes", "Show deletions of messages in the active channel").Default("true").Bool()
+	showHidden    = app.Flag("show-hidden", "List channels you can not view in the channel list").Bool()
+	showVoice     = app.Flag("show-voice", "Show users joining and leaving voice channels in the active guild").Default("true").Bool()
+	overflowMode  = app.Flag("overflow", "How to send messages longer than 2000 characters: ask, split or upload").Default("ask").Enum("ask", "split", "upload")
 )
 
 const (
@@ -76,13 +86,54 @@ func createPrompt(dt *discordterm.Client) string {
 		channel = Red(channel).String()
 	}
 
-	return fmt.Sprintf("#%s>", cutString(channel, 30))
+	// Show who is typing in the active channel
+	var typing string
+	if t := discordterm.FormatTyping(dt.TypingUsers(dt.ActiveChannel())); t != "" {
+		typing = " (" + t + ")"
+		if dt.Conf.ColorText {
+			typing = Gray(typing).String()
+		}
+	}
+
+	return fmt.Sprintf("#%s%s>", cutString(channel, 30), typing)
+}
+
+// typingThreshold is the length a message must reach before we tell
+// Others that we are typing
+const typingThreshold = 30
+
+// typingListener sends typing events while a long message is being typed
+func typingListener(dt *discordterm.Client) readline.Listener {
+	return readline.FuncListener(func(line []rune, pos int, key rune) ([]rune, int, bool) {
+		if dt.ActiveChannel() == "" || len(line) < typingThreshold {
+			return nil, 0, false
+		}
+		text := string(line)
+		if strings.HasPrefix(text, "/say ") || strings.HasPrefix(text, "say ") {
+			dt.SendTyping(dt.ActiveChannel())
+		}
+		return nil, 0, false
+	})
+}
+
+// refreshPrompt keeps the prompt up to date as users start and stop typing
+func refreshPrompt(dt *discordterm.Client, l *readline.Instance) {
+	last := ""
+	for range time.Tick(time.Second) {
+		p := createPrompt(dt)
+		if p != last {
+			l.SetPrompt(p)
+			l.Refresh()
+			last = p
+		}
+	}
 }
 
 const helpMessage = `====| Commands: |==============================================
 /say        say something in the currently active channel
 /gl         lists all the available guilds
-/cl         lists all the available channels in the selected guild
+/cl         lists the channels in the selected guild grouped by category
+            and who is connected to each voice channel
 /leave      leave the current channel to stop listening for messages
 
 /g [n]      selects a guild by index. If no guild is selected,
@@ -92,7 +143,19 @@ const helpMessage = `====| Commands: |==========================================
             read the example for "/cr"
        
 /c [n]      selects a channel by index. If no channel is selected,
-            print information about the current channel
+            print information about the current channel. A channel
+            or thread ID may be given instead of an index
+
+/threads    lists the active and archived threads in the active channel
+
+/thread-new [name] [messageid]  starts a thread in the active channel and
+            selects it. If a message is given the thread is started from it
+
+/posts [tag]  lists the posts in the active forum with their tags, replies and
+            last activity. Select a post with "/c [id]"
+
+/post-new [--tags tag1,tag2] [title]  creates a post in the active forum.
+            The message is entered like a "/p" paragraph
 
 /cr [text]  selects a channel by name with a regular expression
             example: "/cr go_discordgo" will select a channel by the
@@ -102,10 +165,22 @@ const helpMessage = `====| Commands: |==========================================
             will retrieve 10 messages if no argument is specified  
 
 /p [line 1] Send a multi-line paragraph to the current channel
-            Type /send to send the message or cancel to do nothing
+            If $VISUAL or $EDITOR is set the paragraph is written in
+            your editor and sent when you save. Otherwise type /send to
+            send the message or cancel to do nothing
+
+/overflow [ask|split|upload]  Choose how to send messages longer than
+            2000 characters. split sends several messages, keeping code
+            blocks intact, and upload sends the message as a text file
 
 /roles [guildID]    lists the roles in the specified guild, or the current guild.
-/upload [path]      Uploads the file located at 'path' to the current channel
+/upload [paths...] [-s] [-m caption]
+                    Uploads the files located at 'paths' to the current channel
+                    Paths may be glob patterns such as *.png. Quote paths
+                    containing spaces. -s marks the files as spoilers and
+                    -m sends the rest of the line as the message text
+                    Files over the upload limit, 10 MiB unless the guild
+                    is boosted, are skipped
 
 /img-auto [off|on]  Auto image will automatically print message images
                     When set to on.
@@ -117,6 +192,19 @@ const helpMessage = `====| Commands: |==========================================
 /img [message id] [width] displays the given message's images
 /avatar [userid]          displays the avatar of the given user
 
+/save [message id] [n|all] [dir]  downloads the message's n'th attachment, or all
+                                  of its attachments into dir
+/save-all [dir] [--type image|video|audio|text] [--limit n]
+                          downloads the attachments in the channel's history
+                          into dir. Files that were already saved are skipped
+                          without downloading them again
+
+/play [message id] [n]    plays the message's n'th animated image in place
+                          press Ctrl-C to stop playback
+/img-play [on|off]        Automatically play animated images in message history
+/img-loops [n]            The maximum number of times to loop an animation
+                          0 loops until Ctrl-C is pressed
+
 /members [lastid]         displays a list of up to 1000 users in your
                           current guild. Call with lastID to retrieve
                           more users.
@@ -128,6 +216,15 @@ const helpMessage = `====| Commands: |==========================================
                           current guild
 
 /show-nicknames [on|off]  toggle showing users' nicknames in place of their usernames
+/show-edits [on|off]      toggle showing edits to messages in the active channel
+/show-deletes [on|off]    toggle showing deletions of messages in the active channel
+/show-hidden [on|off]     toggle listing channels you can not view in /cl
+/show-voice [on|off]      toggle notices of users joining and leaving voice channels
+
+/perms [user] [channel]   print the effective permissions of a member in the current
+                          guild, or in a channel, and which role or overwrite
+                          granted or denied each one. Defaults to yourself
+                          and the active channel.
 
 /username [username]      Set a new username for your account
 /status  [online|idle|dnd|invisible|offline] Updates your current online status
@@ -140,8 +237,87 @@ const helpMessage = `====| Commands: |==========================================
 /member-nick [userid] [nickname]       Set a member's nickname in the current guild
 /nick [nickname]                       Set your own nickname in the current guild
 
+/role-create [name] [--color --hoist --mentionable --perms]
+            Create a role in the current guild. Roles may be given by name or ID
+/role-edit [role] [--name --color --hoist --mentionable --perms]
+            Edit a role. --perms is a comma separated list of permission names
+            such as send_messages,manage_roles or a number. Prefix every name
+            with + or - to add or remove permissions instead of replacing them.
+            --color is hex like #ff8800. Turn --hoist and --mentionable off with
+            --hoist=off and --mentionable=off
+/role-delete [role]                    Delete a role after asking for confirmation
+/role-move [role] [--position n | --above role | --below role]
+            Move a role in the role list. Position 1 is just above @everyone
+/role-members [role]                   List the members that have a role
+/role-assign [role] --filter [expr] [--dry-run]
+            Give a role to every member matching a filter. The changes are
+            previewed and confirmed before they are made. --dry-run only
+            shows the preview. The filter is a comma separated list of
+            conditions, each may be negated with !: all, bot, human,
+            role:NAME, noroles, name:REGEX, joined<7d, joined>12h, booster
+/role-strip [role] [--filter expr] [--dry-run]
+            Remove a role from every member that has it and matches the filter
+
+/channel-create [name] [--type text|voice|category|announcement|stage|forum]
+            [--category name] [--topic text]  Create a channel in the current guild
+/channel-rename [name] [--channel channel]  Rename the active channel, or another one
+/topic [text]              Print or set the topic of the active channel. "/topic --clear"
+                           removes the topic
+/slowmode [seconds|off]    Print or set the slowmode of the active channel. Durations
+                           like 30s or 5m may be used
+/channel-delete [channel]  Delete a channel, or the active one, after asking for confirmation
+/overwrite [channel] [role|user] [allow|deny|inherit|clear] [perms]
+            Change the permission overwrite of a role or member in a channel.
+            Use "here" for the active channel and @everyone for everyone.
+            perms is a comma separated list of permission names
+
+/kick [user] [--reason text]           Kick a member from the current guild. Users may be
+                                       given by ID, mention or name
+/ban [user] [--delete-days n] [--reason text]
+            Ban a user, deleting their messages from the last n days (at most 7)
+/unban [user] [--reason text]          Remove a user's ban
+/bans [lastID]                         List the bans in the current guild with their
+                                       reasons. Call with lastID to retrieve more bans
+/timeout [user] [duration|off] [--reason text]
+            Stop a member from talking for a duration such as 10m, 2h or 7d
+            (at most 28 days). "off" removes the timeout
+            The --reason of /kick, /ban, /unban and /timeout is the rest of the
+            line, so it must come last
+/audit [--user user] [--action type] [--limit n] [--json] [--out file]
+            Print the current guild's audit log with the changes of each entry.
+            type is an action name such as member_ban_add or role_update.
+            --json prints the entries as JSON and --out writes them to a file
+
+/invite-create [--max-age duration|never] [--max-uses n] [--temporary]
+            Create an invite to the active channel. The default is to
+            expire after 1 day with unlimited uses
+/invites                   List the invites of the current guild with their uses and inviter
+/invite-revoke [code|url]  Revoke an invite
+/invite-info [code|url]    Print the guild and channel an invite leads to
+
+/guild-backup [file]       Save the current guild's settings, roles, channels, permission
+                           overwrites and emojis to a JSON file
+/guild-restore [file] [--dry-run]
+            Compare a backup with the current guild and create or update the roles,
+            channels, emojis and settings that are missing or differ. Nothing is
+            deleted. The changes are previewed and confirmed before they are made.
+            --dry-run only shows the preview
+
 /delete [messageid]       Deletes the message with the given ID in your active channel
-/edit   [messageid]       Edits the message with the given ID in your active channel
+/purge [n] [--user user] [--contains text] [--regex re] [--bots] [--before messageid]
+            Deletes the last n messages matching every filter in your active channel.
+            The messages are previewed and confirmed before they are deleted
+/pins                     Lists the pinned messages in your active channel
+/pin   [messageid]        Pins the message with the given ID in your active channel
+/unpin [messageid]        Unpins the message with the given ID in your active channel
+/edit   [messageid] [text]  Edits the message with the given ID in your active channel
+                            Without text, the message is opened in your editor
+/reply  [messageid] [text]  Replies to the message with the given ID in your active channel
+                            Without text, the reply is written in your editor
+s/pattern/replacement/[gi] Corrects your last message in the active channel
+                            pattern is a regular expression. g replaces every
+                            match and i ignores case
+/undo                       Deletes your last message in the active channel
 
 /ls [n]     If you are not in a guild, lists guilds
             If you are in a guild but not in a channel, lists channels
@@ -183,8 +359,13 @@ var commandList = []string{
 	"gr",
 	"c",
 	"cr",
+	"threads",
+	"thread-new",
+	"posts",
+	"post-new",
 	"m",
 	"p",
+	"overflow",
 	"roles",
 	"upload",
 	"img-auto",
@@ -193,10 +374,20 @@ var commandList = []string{
 	"img-width",
 	"img-color",
 	"avatar",
+	"save",
+	"save-all",
+	"play",
+	"img-play",
+	"img-loops",
 	"members",
 	"presences",
 	"member-info",
 	"show-nicknames",
+	"show-edits",
+	"show-deletes",
+	"show-hidden",
+	"show-voice",
+	"perms",
 	"username",
 	"status",
 	"playing",
@@ -206,8 +397,39 @@ var commandList = []string{
 	"member-remove-role",
 	"member-nick",
 	"nick",
+	"role-create",
+	"role-edit",
+	"role-delete",
+	"role-move",
+	"role-members",
+	"role-assign",
+	"role-strip",
+	"channel-create",
+	"channel-rename",
+	"topic",
+	"slowmode",
+	"channel-delete",
+	"overwrite",
+	"kick",
+	"ban",
+	"unban",
+	"bans",
+	"timeout",
+	"audit",
+	"invite-create",
+	"invites",
+	"invite-revoke",
+	"invite-info",
+	"guild-backup",
+	"guild-restore",
 	"delete",
+	"purge",
+	"pins",
+	"pin",
+	"unpin",
 	"edit",
+	"reply",
+	"undo",
 	"ls",
 	"cd",
 	"help",
@@ -226,11 +448,13 @@ func readInputLoop(dt *discordterm.Client) {
 		AutoComplete:      completer,
 		HistoryFile:       filepath.Join(os.TempDir(), historyFile),
 		HistorySearchFold: true,
+		Listener:          typingListener(dt),
 	})
 	if err != nil {
 		log.Println("Error creating readline:", err)
 		return
 	}
+	go refreshPrompt(dt, l)
 
 	var standardReader bool
 
@@ -285,6 +509,68 @@ func (a Args) After(n int) string {
 	return ""
 }
 
+// Flags holds the --flag arguments of a command
+type Flags map[string]string
+
+// Has returns true if the flag was provided
+func (f Flags) Has(name string) bool {
+	_, ok := f[name]
+	return ok
+}
+
+// Get returns the value of a flag, or an empty string
+func (f Flags) Get(name string) string {
+	return f[name]
+}
+
+// parseFlags separates --flag arguments from the rest of the arguments.
+// Flags take the following argument as their value, or may be written as --flag=value.
+// Flags listed in boolFlags, and flags followed by another flag, do not take a value.
+func parseFlags(args Args, boolFlags ...string) (Args, Flags) {
+	rest := Args{}
+	flags := Flags{}
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if !strings.HasPrefix(a, "--") || len(a) == 2 {
+			rest = append(rest, a)
+			continue
+		}
+		name := a[2:]
+		if n := strings.Index(name, "="); n != -1 {
+			flags[name[:n]] = name[n+1:]
+			continue
+		}
+
+		isBool := false
+		for _, b := range boolFlags {
+			if b == name {
+				isBool = true
+			}
+		}
+		if isBool || i+1 >= len(args) || (strings.HasPrefix(args[i+1], "--") && len(args[i+1]) > 2) {
+			flags[name] = ""
+			continue
+		}
+		flags[name] = args[i+1]
+		i++
+	}
+	return rest, flags
+}
+
+// parseReason separates a --reason flag from the arguments before it.
+// The reason is the rest of the line so that it does not need quotes.
+func parseReason(args Args) (Args, string) {
+	for i, a := range args {
+		if a == "--reason" {
+			return args[:i], strings.Join(args[i+1:], " ")
+		}
+		if strings.HasPrefix(a, "--reason=") {
+			return args[:i], strings.TrimPrefix(strings.Join(args[i:], " "), "--reason=")
+		}
+	}
+	return args, ""
+}
+
 func parseArgsCsv(line string) ([]string, error) {
 	rd := csv.NewReader(bytes.NewBufferString(line))
 	rd.Comma = ' '
@@ -301,6 +587,19 @@ func executeCommand(dt *discordterm.Client, line string) error {
 	// if strings.HasPrefix(line, "") {
 	// Remove the prefix from the command
 
+	// Correct the last message with s/pattern/replacement/
+	if discordterm.IsSubstitution(line) {
+		if dt.ActiveChannel() == "" {
+			return errors.New("You need to be in a channel to correct a message")
+		}
+		m, err := dt.CorrectLastMessage(dt.ActiveChannel(), line)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Edited message to:", m.Content)
+		return nil
+	}
+
 	if strings.HasPrefix(line, "/") { // Remove slash prefix if it exists
 		line = line[1:]
 	}
@@ -336,7 +635,7 @@ func executeCommand(dt *discordterm.Client, line string) error {
 		if dt.ActiveGuild() == "" {
 			return errors.New("You need to select a guild first")
 		}
-		channels, err := dt.Cli.GuildChannels(dt.ActiveGuild())
+		tree, err := dt.ChannelTree(dt.ActiveGuild())
 		if err != nil {
 			return err
 		}
@@ -345,20 +644,48 @@ func executeCommand(dt *discordterm.Client, line string) error {
 			return err
 		}
 		fmt.Println(guild.Name)
-		for i, c := range channels {
-			if c.Type == discordgo.ChannelTypeGuildVoice {
+		for _, e := range tree {
+			c := e.Channel
+			name := discordterm.ChannelTypeGlyph(c.Type) + " " + c.Name
+			indent := strings.Repeat("  ", e.Depth)
+
+			// Categories are headings and can not be selected
+			if e.Index < 0 {
+				if dt.Conf.ColorText && e.Hidden {
+					fmt.Println(" ", "\t", Gray(strings.ToUpper(name)))
+				} else if dt.Conf.ColorText {
+					fmt.Println(" ", "\t", Brown(strings.ToUpper(name)))
+				} else {
+					fmt.Println(" ", "\t", strings.ToUpper(name))
+				}
+				continue
+			}
+
+			// Channels we can not view are only listed when show-hidden is on
+			if e.Hidden {
+				if dt.Conf.ColorText {
+					fmt.Println(Gray(e.Index), "\t", indent+Gray(name).String())
+				} else {
+					fmt.Println(e.Index, "\t", indent+name, "(hidden)")
+				}
 				continue
 			}
+
 			if dt.Conf.ColorText {
 				if dt.ActiveChannel() == c.ID {
-					fmt.Println(Magenta(i), "\t", Magenta(c.Name))
+					fmt.Println(Magenta(e.Index), "\t", indent+Magenta(name).String())
 				} else if n := dt.ChannelUnreadMessages(guild.ID, c.ID); n > 0 {
-					fmt.Println(Green(i), "\t", Green(c.Name), Red("["+strconv.Itoa(n)+"]"))
+					fmt.Println(Green(e.Index), "\t", indent+Green(name).String(), Red("["+strconv.Itoa(n)+"]"))
 				} else {
-					fmt.Println(i, "\t", c.Name)
+					fmt.Println(e.Index, "\t", indent+name)
 				}
 			} else {
-				fmt.Println(i, "\t", c.Name)
+				fmt.Println(e.Index, "\t", indent+name)
+			}
+
+			// List who is connected to voice channels
+			if discordterm.IsVoiceType(c.Type) {
+				printVoiceMembers(dt, guild.ID, c.ID, indent+"    ")
 			}
 		}
 
@@ -392,8 +719,12 @@ func executeCommand(dt *discordterm.Client, line string) error {
 
 	//Select guild by index
 	case "g", "guild":
+		// Print information about the current guild
 		if args.Get(1) == "" {
-			return errors.New("Please select a guild index")
+			if dt.ActiveGuild() == "" {
+				return errors.New("Please select a guild index")
+			}
+			return dt.PrintGuildInfo(dt.ActiveGuild(), dt.Conf)
 		}
 		n, err := strconv.Atoi(args.Get(1))
 		if err != nil {
@@ -429,17 +760,35 @@ func executeCommand(dt *discordterm.Client, line string) error {
 
 	// Select channel
 	case "c", "channel":
+		// Select a channel or thread by ID
+		if discordterm.IsSnowflake(args.Get(1)) {
+			channel, err := dt.Channel(args.Get(1))
+			if err != nil {
+				return err
+			}
+			if channel.GuildID != "" {
+				dt.SetGuild(channel.GuildID)
+			}
+			dt.SetChannel(channel.ID)
+			dt.MarkRead(channel.GuildID, channel.ID)
+			printSelectedChannel(channel)
+			return nil
+		}
 		if dt.ActiveGuild() == "" {
 			return errors.New("You need to select a guild first")
 		}
+		// Print information about the current channel
 		if args.Get(1) == "" {
-			return errors.New("Please select a channel index")
+			if dt.ActiveChannel() == "" {
+				return errors.New("Please select a channel index")
+			}
+			return dt.PrintChannelInfo(dt.ActiveChannel(), dt.Conf)
 		}
 		n, err := strconv.Atoi(args.Get(1))
 		if err != nil {
 			return err
 		}
-		channels, err := dt.Cli.GuildChannels(dt.ActiveGuild())
+		channels, err := dt.SelectableChannels(dt.ActiveGuild())
 		if err != nil {
 			return err
 		}
@@ -450,7 +799,166 @@ func executeCommand(dt *discordterm.Client, line string) error {
 		dt.SetChannel(channels[n].ID)
 		// Mark channel messages as read
 		dt.MarkRead(dt.ActiveGuild(), channels[n].ID)
-		fmt.Printf("Selected channel: %s\n", channels[n].Name)
+		printSelectedChannel(channels[n])
+
+	// List the threads in the active channel
+	case "threads":
+		if dt.ActiveChannel() == "" {
+			return errors.New("You need to select a channel first")
+		}
+		channel, err := dt.Channel(dt.ActiveChannel())
+		if err != nil {
+			return err
+		}
+		// List the other threads of a thread's channel
+		if discordterm.IsThreadType(channel.Type) {
+			channel, err = dt.Channel(channel.ParentID)
+			if err != nil {
+				return err
+			}
+		}
+		threads, err := dt.ChannelThreads(channel.ID)
+		if err != nil {
+			return err
+		}
+		if len(threads) == 0 {
+			fmt.Println("There are no threads in #" + channel.Name)
+			return nil
+		}
+		for _, t := range threads {
+			name := discordterm.ChannelTypeGlyph(t.Type) + " " + t.Name
+			info := fmt.Sprintf("[%d messages]", t.MessageCount)
+			if t.Archived() {
+				info += " (archived)"
+			}
+			if dt.Conf.ColorText {
+				if dt.ActiveChannel() == t.ID {
+					fmt.Println(Cyan(t.ID), "\t", Magenta(name), info)
+				} else if n := dt.ChannelUnreadMessages(t.GuildID, t.ID); n > 0 {
+					fmt.Println(Cyan(t.ID), "\t", Green(name), info, Red("["+strconv.Itoa(n)+"]"))
+				} else if t.Archived() {
+					fmt.Println(Cyan(t.ID), "\t", Gray(name), Gray(info))
+				} else {
+					fmt.Println(Cyan(t.ID), "\t", name, info)
+				}
+			} else {
+				fmt.Println(t.ID, "\t", name, info)
+			}
+		}
+
+	// Start a new thread
+	case "thread-new":
+		if dt.ActiveChannel() == "" {
+			return errors.New("You need to select a channel first")
+		}
+		if args.Get(1) == "" {
+			return errors.New("Please provide a name for the thread")
+		}
+		var messageID string
+		if args.Get(2) != "" {
+			id, err := resolveMessageID(dt, args.Get(2))
+			if err != nil {
+				return err
+			}
+			messageID = id
+		}
+		thread, err := dt.StartThread(dt.ActiveChannel(), messageID, args.Get(1))
+		if err != nil {
+			return err
+		}
+		dt.SetChannel(thread.ID)
+		fmt.Printf("Started thread: %s\n", thread.Name)
+
+	// List the posts in the active forum
+	case "posts":
+		forum, err := activeForum(dt)
+		if err != nil {
+			return err
+		}
+		posts, err := dt.ForumPosts(forum.ID)
+		if err != nil {
+			return err
+		}
+		if len(forum.AvailableTags) > 0 {
+			tags := []string{}
+			for i := range forum.AvailableTags {
+				tags = append(tags, discordterm.FormatForumTag(&forum.AvailableTags[i]))
+			}
+			fmt.Println("Tags:", strings.Join(tags, ", "))
+		}
+
+		var tag *discordgo.ForumTag
+		if args.Get(1) != "" {
+			if tag = forum.Tag(args.After(1)); tag == nil {
+				return errors.New("The forum has no tag named " + args.After(1))
+			}
+		}
+		shown := 0
+		for _, p := range posts {
+			if tag != nil && !containsString(p.AppliedTags, tag.ID) {
+				continue
+			}
+			shown++
+			info := fmt.Sprintf("%d replies, active %s ago", p.MessageCount, discordterm.FormatAge(time.Since(p.LastActivity())))
+			if p.Archived() {
+				info += " (archived)"
+			}
+			tags := ""
+			if names := forum.TagNames(p.AppliedTags); len(names) > 0 {
+				tags = "[" + strings.Join(names, ", ") + "]"
+			}
+			if dt.Conf.ColorText {
+				if dt.ActiveChannel() == p.ID {
+					fmt.Println(Cyan(p.ID), "\t", Magenta(p.Name), Brown(tags), info)
+				} else if n := dt.ChannelUnreadMessages(p.GuildID, p.ID); n > 0 {
+					fmt.Println(Cyan(p.ID), "\t", Green(p.Name), Brown(tags), info, Red("["+strconv.Itoa(n)+"]"))
+				} else {
+					fmt.Println(Cyan(p.ID), "\t", p.Name, Brown(tags), Gray(info))
+				}
+			} else {
+				fmt.Println(p.ID, "\t", p.Name, tags, info)
+			}
+		}
+		if shown == 0 {
+			fmt.Println("There are no posts in " + forum.Name)
+		}
+
+	// Create a post in the active forum
+	case "post-new":
+		forum, err := activeForum(dt)
+		if err != nil {
+			return err
+		}
+		rest, flags := parseFlags(args)
+		title := rest.After(1)
+		if title == "" {
+			return errors.New("Please provide a title for the post")
+		}
+		tagIDs := []string{}
+		if flags.Get("tags") != "" {
+			for _, name := range strings.Split(flags.Get("tags"), ",") {
+				tag := forum.Tag(strings.TrimSpace(name))
+				if tag == nil {
+					return errors.New("The forum has no tag named " + name)
+				}
+				tagIDs = append(tagIDs, tag.ID)
+			}
+		}
+
+		content, err := composeParagraph("")
+		if err == errEmptyMessage || err == errCancelled {
+			fmt.Println(err)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		post, err := dt.CreateForumPost(forum.ID, title, tagIDs, content)
+		if err != nil {
+			return err
+		}
+		dt.SetChannel(post.ID)
+		fmt.Printf("Created post: %s\n", post.Name)
 
 	// Select channel regex
 	case "cr", "channel_regex":
@@ -464,7 +972,7 @@ func executeCommand(dt *discordterm.Client, line string) error {
 		if err != nil {
 			return err
 		}
-		channels, err := dt.Cli.GuildChannels(dt.ActiveGuild())
+		channels, err := dt.SelectableChannels(dt.ActiveGuild())
 		if err != nil {
 			return err
 		}
@@ -503,28 +1011,74 @@ func executeCommand(dt *discordterm.Client, line string) error {
 			dt.PrintMessage(messages[i])
 		}
 
-	// Uploads a file
+	// Uploads files
 	case "upload":
 		if dt.ActiveChannel() == "" {
 			return errors.New("You need to be in a channel to upload a file")
 		}
-		if args.Get(1) == "" {
+
+		var (
+			patterns []string
+			caption  string
+			spoiler  bool
+		)
+		for i := 1; i < len(args); i++ {
+			switch args[i] {
+			case "-m", "--message":
+				caption = args.After(i + 1)
+				i = len(args)
+			case "-s", "--spoiler":
+				spoiler = true
+			default:
+				patterns = append(patterns, args[i])
+			}
+		}
+		if len(patterns) == 0 {
 			return errors.New("Please enter a file path to upload")
 		}
 
-		// Get file stats for name
-		finfo, err := os.Stat(args.After(1))
-		if err != nil {
-			return err
-		}
+		limit := dt.UploadLimit(dt.ActiveChannel())
+		uploads := discordterm.OpenUploads(patterns, limit, spoiler)
+		defer func() {
+			for _, u := range uploads {
+				u.Close()
+			}
+		}()
 
-		f, err := os.Open(args.After(1))
-		if err != nil {
-			return err
+		// Group the files into messages that fit within the
+		// Upload limit and the maximum number of attachments
+		batches := [][]*discordterm.Upload{}
+		var (
+			batch     []*discordterm.Upload
+			batchSize int64
+		)
+		for _, u := range uploads {
+			if u.Err != nil {
+				printUploadResult(dt, u, u.Err)
+				continue
+			}
+			if len(batch) == discordterm.MaxUploadFiles || batchSize+u.Size > limit {
+				batches = append(batches, batch)
+				batch, batchSize = nil, 0
+			}
+			batch = append(batch, u)
+			batchSize += u.Size
+		}
+		if len(batch) > 0 {
+			batches = append(batches, batch)
 		}
-		defer f.Close()
 
-		dt.Cli.ChannelFileSend(dt.ActiveChannel(), finfo.Name(), f)
+		for i, b := range batches {
+			// Only attach the caption to the first message
+			text := ""
+			if i == 0 {
+				text = caption
+			}
+			_, err := dt.SendUploads(dt.ActiveChannel(), text, b)
+			for _, u := range b {
+				printUploadResult(dt, u, err)
+			}
+		}
 
 	// set the default width of images
 	case "img-width":
@@ -602,21 +1156,11 @@ func executeCommand(dt *discordterm.Client, line string) error {
 			width = dt.Conf.ImageWidth
 		}
 
-		messages, err := dt.Cli.ChannelMessages(dt.ActiveChannel(), 100, "", "", "")
+		m, err := findMessage(dt, args.Get(1))
 		if err != nil {
 			return err
 		}
 
-		var m *discordgo.Message
-		for _, message := range messages {
-			if strings.Contains(message.ID, args.Get(1)) {
-				m = message
-			}
-		}
-		if m == nil {
-			return errors.New("Message ID was not in the past 100 messages, or did not contain the given substring")
-		}
-
 		dt.PrintMessageComplex(m, &discordterm.Config{
 			ColorText:   dt.Conf.ColorText,
 			ImageWidth:  width,
@@ -625,6 +1169,196 @@ func executeCommand(dt *discordterm.Client, line string) error {
 			ColorImages: dt.Conf.ColorImages,
 		})
 
+	// Plays a message's animated images in place
+	case "play":
+		if dt.ActiveChannel() == "" {
+			return errors.New("You need to be in a channel to use this command")
+		}
+		if args.Get(1) == "" {
+			return errors.New("Please provide a message ID")
+		}
+
+		m, err := findMessage(dt, args.Get(1))
+		if err != nil {
+			return err
+		}
+		urls := messageImageURLs(m)
+		if len(urls) == 0 {
+			return errors.New("Message has no images")
+		}
+
+		n := 0
+		if args.Get(2) != "" {
+			n, err = strconv.Atoi(args.Get(2))
+			if err != nil {
+				return err
+			}
+		}
+		if n >= len(urls) || n < 0 {
+			return errors.New("Index out of bounds")
+		}
+
+		fmt.Println("Press Ctrl-C to stop playback")
+		return dt.PlayImageURL(urls[n])
+
+	// Download a message's attachments
+	case "save":
+		if dt.ActiveChannel() == "" {
+			return errors.New("You need to be in a channel to use this command")
+		}
+		if args.Get(1) == "" {
+			return errors.New("Please provide a message ID")
+		}
+
+		m, err := findMessage(dt, args.Get(1))
+		if err != nil {
+			return err
+		}
+		if len(m.Attachments) == 0 {
+			return errors.New("Message has no attachments")
+		}
+
+		attachments := m.Attachments
+		if args.Get(2) != "" && args.Get(2) != "all" {
+			n, err := strconv.Atoi(args.Get(2))
+			if err != nil {
+				return err
+			}
+			if n >= len(attachments) || n < 0 {
+				return errors.New("Index out of bounds")
+			}
+			attachments = attachments[n : n+1]
+		}
+
+		dir := args.Get(3)
+		if dir == "" {
+			dir = "."
+		}
+		for _, a := range attachments {
+			path, existed, err := dt.SaveAttachment(a, dir)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+			printSaved(dt, path, existed)
+		}
+
+	// Download every attachment in the active channel's history
+	case "save-all":
+		if dt.ActiveChannel() == "" {
+			return errors.New("You need to be in a channel to use this command")
+		}
+		rest, flags := parseFlags(args[1:])
+
+		limit := 0
+		if flags.Has("limit") {
+			n, err := strconv.Atoi(flags.Get("limit"))
+			if err != nil || n <= 0 {
+				return errors.New("Invalid limit")
+			}
+			limit = n
+		}
+		fileType := strings.ToLower(flags.Get("type"))
+
+		dir := rest.Get(0)
+		if dir == "" {
+			dir = "attachments"
+			if c, err := dt.Cli.State.Channel(dt.ActiveChannel()); err == nil {
+				dir = discordterm.SanitizeFilename(c.Name)
+			}
+		}
+
+		// Attachments saved before are skipped without downloading them
+		index := discordterm.SavedAttachments(dir)
+		saved, skipped, failed := 0, 0, 0
+		before := ""
+	history:
+		for {
+			messages, err := dt.Cli.ChannelMessages(dt.ActiveChannel(), 100, before, "", "")
+			if err != nil {
+				return err
+			}
+			if len(messages) == 0 {
+				break
+			}
+			before = messages[len(messages)-1].ID
+
+			for _, m := range messages {
+				for _, a := range m.Attachments {
+					if fileType != "" && fileType != "any" && discordterm.AttachmentType(a.Filename) != fileType {
+						continue
+					}
+					if limit > 0 && saved+skipped+failed >= limit {
+						break history
+					}
+					if path, ok := index[a.ID]; ok {
+						skipped++
+						printSaved(dt, path, true)
+						continue
+					}
+					path, existed, err := dt.SaveAttachment(a, dir)
+					if err != nil {
+						log.Println(err)
+						failed++
+						continue
+					}
+					if existed {
+						skipped++
+					} else {
+						saved++
+					}
+					printSaved(dt, path, existed)
+				}
+			}
+		}
+		fmt.Printf("Saved %d attachments to %s, %d already saved, %d failed\n", saved, dir, skipped, failed)
+
+	// Set how messages that are too long are sent
+	case "overflow":
+		if args.Get(1) == "" {
+			fmt.Println(dt.Conf.OverflowMode)
+			return nil
+		}
+		switch mode := strings.ToLower(args.Get(1)); mode {
+		case discordterm.OverflowAsk, discordterm.OverflowSplit, discordterm.OverflowUpload:
+			dt.Conf.OverflowMode = mode
+			fmt.Println("Long messages will be handled with:", mode)
+		default:
+			return errors.New("Please choose one of ask, split or upload")
+		}
+
+	// Automatically play animated images
+	case "img-play":
+		if args.Get(1) == "" {
+			fmt.Println(formatBoolOnOff(dt.Conf.AutoPlay))
+			return nil
+		}
+		if isOn(args.Get(1)) {
+			dt.Conf.AutoPlay = true
+			fmt.Println("Animated images will be played")
+		}
+		if isOff(args.Get(1)) {
+			dt.Conf.AutoPlay = false
+			fmt.Println("Only the first frame of animated images will be shown")
+		}
+
+	// Set the maximum number of times an animation loops
+	case "img-loops":
+		if args.Get(1) == "" {
+			fmt.Println(dt.Conf.AnimationLoops)
+			return nil
+		}
+		n, err := strconv.Atoi(args.Get(1))
+		if err != nil || n < 0 {
+			return errors.New("Invalid number")
+		}
+		dt.Conf.AnimationLoops = n
+		if n == 0 {
+			fmt.Println("Animations will loop until Ctrl-C is pressed")
+		} else {
+			fmt.Println("Animations will loop at most", n, "times")
+		}
+
 	// Prints a user's avatar
 	case "avatar":
 		if dt.ActiveGuild() == "" {
@@ -667,30 +1401,20 @@ func executeCommand(dt *discordterm.Client, line string) error {
 			return errors.New("You need to be in a channel to use this command")
 		}
 
-		lines := []string{}
-		if args.After(1) != "" {
-			lines = append(lines, args.After(1))
-		}
-
-		rd := bufio.NewReader(os.Stdin)
+		// Let others know we are writing while the paragraph is composed
+		stopTyping := dt.StartTyping(dt.ActiveChannel())
+		defer stopTyping()
 
-		fmt.Println("Enter your paragraph in multiple lines. Type /send or /cancel to finish")
-	loop:
-		for {
-			line := strings.Trim(ReadInputString(rd), "\r\n")
-			switch line {
-			case "/send":
-				_, err := dt.Cli.ChannelMessageSend(dt.ActiveChannel(), strings.Join(lines, "\n"))
-				if err != nil {
-					return err
-				}
-				break loop
-			case "/cancel":
-				break loop
-			default:
-				lines = append(lines, line)
-			}
+		// Compose the paragraph in the user's editor when they have one
+		content, err := composeParagraph(args.After(1))
+		if err == errEmptyMessage || err == errCancelled {
+			fmt.Println(err)
+			return nil
+		}
+		if err != nil {
+			return err
 		}
+		return sendMessage(dt, content)
 
 	// Print a list of guild members
 	case "members":
@@ -741,8 +1465,8 @@ func executeCommand(dt *discordterm.Client, line string) error {
 			}
 
 			var game string
-			if p.Game != nil {
-				game = p.Game.Name
+			if len(p.Activities) > 0 {
+				game = p.Activities[0].Name
 			}
 
 			nicknamePadLeft := strings.Repeat(" ", MaxInt(0, 35-utf8.RuneCountInString(m.User.Username)))
@@ -769,6 +1493,75 @@ func executeCommand(dt *discordterm.Client, line string) error {
 		// Refresh the message list after deletion
 		executeCommand(dt, "/m 25")
 
+	// Delete many messages at once
+	case "purge":
+		if dt.ActiveChannel() == "" {
+			return errors.New("You need to be in a channel to use this command")
+		}
+		rest, flags := parseFlags(args, "bots")
+		n, err := strconv.Atoi(rest.Get(1))
+		if err != nil || n < 1 {
+			return errors.New("Please provide the number of messages to delete")
+		}
+
+		filter := &discordterm.MessageFilter{
+			Contains: flags.Get("contains"),
+			Bots:     flags.Has("bots"),
+		}
+		if flags.Has("user") {
+			user, err := dt.ResolveUser(dt.ActiveGuild(), flags.Get("user"))
+			if err != nil {
+				return err
+			}
+			filter.UserID = user.ID
+		}
+		if flags.Has("regex") {
+			re, err := regexp.Compile(flags.Get("regex"))
+			if err != nil {
+				return err
+			}
+			filter.Regex = re
+		}
+		before := ""
+		if flags.Has("before") {
+			before, err = resolveMessageID(dt, flags.Get("before"))
+			if err != nil {
+				return err
+			}
+		}
+
+		messages, err := dt.SelectMessages(dt.ActiveChannel(), n, before, filter)
+		if err != nil {
+			return err
+		}
+		if len(messages) == 0 {
+			fmt.Println("No messages matched")
+			return nil
+		}
+		fmt.Printf("%d messages will be deleted:\n", len(messages))
+		for i, m := range messages {
+			if i == previewLimit {
+				fmt.Printf("... and %d more\n", len(messages)-previewLimit)
+				break
+			}
+			content := strings.Replace(cutString(m.Content, 60), "\n", " ", -1)
+			if dt.Conf.ColorText {
+				fmt.Println(Blue(m.ID), Red(m.Author.Username+":"), content)
+			} else {
+				fmt.Println(m.ID, m.Author.Username+":", content)
+			}
+		}
+		if !confirm("Delete these messages?") {
+			fmt.Println("Cancelled")
+			return nil
+		}
+
+		deleted, failures := dt.PurgeMessages(dt.ActiveChannel(), messages, os.Stdout)
+		fmt.Printf("Deleted %d messages\n", deleted)
+		for _, err := range failures {
+			fmt.Println("  failed:", err)
+		}
+
 	case "edit":
 		if dt.ActiveChannel() == "" {
 			return errors.New("You need to be in a channel to use this command")
@@ -776,14 +1569,129 @@ func executeCommand(dt *discordterm.Client, line string) error {
 		if args.Get(1) == "" {
 			return errors.New("Please specify a message id")
 		}
-		// Replace the message with the second argument
-		_, err := dt.Cli.ChannelMessageEdit(dt.ActiveChannel(), args.Get(1), args.After(2))
+		// Replace the message with the second argument, or with the
+		// Contents of the editor if no text was given
+		content := args.After(2)
+		if content == "" {
+			m, err := dt.Cli.ChannelMessage(dt.ActiveChannel(), args.Get(1))
+			if err != nil {
+				return err
+			}
+			content, err = composeInEditor(m.Content)
+			if err == errEmptyMessage {
+				fmt.Println(err)
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if content == m.Content {
+				fmt.Println("Message was not changed")
+				return nil
+			}
+		}
+		_, err := dt.Cli.ChannelMessageEdit(dt.ActiveChannel(), args.Get(1), content)
 		if err != nil {
 			return err
 		}
 		// Refresh the list of messages after editing"
 		executeCommand(dt, "/m 25")
 
+	// List the pinned messages in the active channel
+	case "pins":
+		if dt.ActiveChannel() == "" {
+			return errors.New("You need to be in a channel to use this command")
+		}
+		messages, err := dt.Cli.ChannelMessagesPinned(dt.ActiveChannel())
+		if err != nil {
+			return err
+		}
+		if len(messages) == 0 {
+			fmt.Println("No pinned messages")
+			return nil
+		}
+		for i := len(messages) - 1; i >= 0; i-- {
+			dt.PrintMessage(messages[i])
+		}
+
+	// Pin a message in the active channel
+	case "pin":
+		if dt.ActiveChannel() == "" {
+			return errors.New("You need to be in a channel to use this command")
+		}
+		if args.Get(1) == "" {
+			return errors.New("Please provide a message ID")
+		}
+		id, err := resolveMessageID(dt, args.Get(1))
+		if err != nil {
+			return err
+		}
+		err = dt.Cli.ChannelMessagePin(dt.ActiveChannel(), id)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Pinned message", id)
+
+	// Unpin a message in the active channel
+	case "unpin":
+		if dt.ActiveChannel() == "" {
+			return errors.New("You need to be in a channel to use this command")
+		}
+		if args.Get(1) == "" {
+			return errors.New("Please provide a message ID")
+		}
+		id, err := resolveMessageID(dt, args.Get(1))
+		if err != nil {
+			return err
+		}
+		err = dt.Cli.ChannelMessageUnpin(dt.ActiveChannel(), id)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Unpinned message", id)
+
+	// Delete your last message in the active channel
+	case "undo":
+		if dt.ActiveChannel() == "" {
+			return errors.New("You need to be in a channel to use this command")
+		}
+		m, err := dt.UndoLastMessage(dt.ActiveChannel())
+		if err != nil {
+			return err
+		}
+		fmt.Println("Deleted message:", m.Content)
+
+	// Reply to a message
+	case "reply":
+		if dt.ActiveChannel() == "" {
+			return errors.New("You need to be in a channel to use this command")
+		}
+		if args.Get(1) == "" {
+			return errors.New("Please specify a message id")
+		}
+		content := args.After(2)
+		if content == "" {
+			var err error
+			content, err = composeInEditor("")
+			if err == errEmptyMessage {
+				fmt.Println(err)
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+		err := sendMessageComplex(dt, &discordgo.MessageSend{
+			Content: content,
+			Reference: &discordgo.MessageReference{
+				MessageID: args.Get(1),
+				ChannelID: dt.ActiveChannel(),
+			},
+		})
+		if err != nil {
+			return err
+		}
+
 	// List the roles in a guild
 	case "roles":
 		var guildID string
@@ -793,23 +1701,761 @@ func executeCommand(dt *discordterm.Client, line string) error {
 			if dt.ActiveGuild() == "" {
 				return errors.New("Supply a guildID or enter a guild to use this command")
 			}
-			guildID = dt.ActiveGuild()
+			guildID = dt.ActiveGuild()
+		}
+
+		guild, err := dt.Cli.State.Guild(guildID)
+		if err != nil {
+			return err
+		}
+		if len(guild.Roles) == 0 {
+			return errors.New("No roles found")
+		}
+		sort.Sort(discordgo.Roles(guild.Roles))
+		for _, role := range guild.Roles {
+			if dt.Conf.ColorText {
+				fmt.Println(Cyan(role.ID), "\t", Green(role.Name))
+			} else {
+				fmt.Println(role.ID, "\t", role.Name)
+			}
+		}
+
+	// Create a role
+	case "role-create":
+		if dt.ActiveGuild() == "" {
+			return errors.New("You need to be in a guild to use this command")
+		}
+		rest, flags := parseFlags(args, "hoist", "mentionable")
+		if rest.After(1) == "" {
+			return errors.New("Please provide a name for the role")
+		}
+		params, err := roleParams(flags, 0)
+		if err != nil {
+			return err
+		}
+		params.Name = rest.After(1)
+		role, err := dt.CreateRole(dt.ActiveGuild(), params)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Created role", role.Name, role.ID)
+
+	// Edit a role
+	case "role-edit":
+		if dt.ActiveGuild() == "" {
+			return errors.New("You need to be in a guild to use this command")
+		}
+		rest, flags := parseFlags(args, "hoist", "mentionable")
+		if rest.After(1) == "" || len(flags) == 0 {
+			return errors.New("Please provide a role and the fields to change")
+		}
+		role, err := dt.ResolveRole(dt.ActiveGuild(), rest.After(1))
+		if err != nil {
+			return err
+		}
+		params, err := roleParams(flags, int64(role.Permissions))
+		if err != nil {
+			return err
+		}
+		params.Name = flags.Get("name")
+		role, err = dt.EditRole(dt.ActiveGuild(), role.ID, params)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Edited role", role.Name)
+		if params.Permissions != nil {
+			fmt.Println("Permissions:", strings.Join(discordterm.PermissionNames(*params.Permissions), ", "))
+		}
+
+	// Delete a role
+	case "role-delete":
+		if dt.ActiveGuild() == "" {
+			return errors.New("You need to be in a guild to use this command")
+		}
+		if args.After(1) == "" {
+			return errors.New("Please provide a role to delete")
+		}
+		role, err := dt.ResolveRole(dt.ActiveGuild(), args.After(1))
+		if err != nil {
+			return err
+		}
+		if !confirm(fmt.Sprintf("Delete the role %s (%s)?", role.Name, role.ID)) {
+			fmt.Println("Cancelled")
+			return nil
+		}
+		err = dt.DeleteRole(dt.ActiveGuild(), role.ID)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Deleted role", role.Name)
+
+	// Move a role in the role list
+	case "role-move":
+		if dt.ActiveGuild() == "" {
+			return errors.New("You need to be in a guild to use this command")
+		}
+		rest, flags := parseFlags(args)
+		if rest.After(1) == "" {
+			return errors.New("Please provide a role to move")
+		}
+		role, err := dt.ResolveRole(dt.ActiveGuild(), rest.After(1))
+		if err != nil {
+			return err
+		}
+		position, err := rolePosition(dt, role, flags)
+		if err != nil {
+			return err
+		}
+		err = dt.MoveRole(dt.ActiveGuild(), role.ID, position)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Moved role", role.Name, "to position", position)
+
+	// List the members with a role
+	case "role-members":
+		if dt.ActiveGuild() == "" {
+			return errors.New("You need to be in a guild to use this command")
+		}
+		if args.After(1) == "" {
+			return errors.New("Please provide a role")
+		}
+		role, err := dt.ResolveRole(dt.ActiveGuild(), args.After(1))
+		if err != nil {
+			return err
+		}
+		members, err := fetchMembers(dt)
+		if err != nil {
+			return err
+		}
+		count := 0
+		for _, m := range members {
+			if !discordterm.HasRole(m, role.ID) {
+				continue
+			}
+			count++
+			printMemberLine(dt, m)
+		}
+		fmt.Printf("%d members have the role %s\n", count, role.Name)
+
+	// Add or remove a role from many members
+	case "role-assign", "role-strip":
+		if dt.ActiveGuild() == "" {
+			return errors.New("You need to be in a guild to use this command")
+		}
+		add := args.Get(0) == "role-assign"
+		rest, flags := parseFlags(args, "dry-run")
+		if rest.After(1) == "" {
+			return errors.New("Please provide a role")
+		}
+		if add && flags.Get("filter") == "" {
+			return errors.New("Please provide the members to give the role to with --filter, or --filter all for everyone")
+		}
+		role, err := dt.ResolveRole(dt.ActiveGuild(), rest.After(1))
+		if err != nil {
+			return err
+		}
+		expr := flags.Get("filter")
+		if expr == "" {
+			expr = "all"
+		}
+		filter, err := dt.ParseMemberFilter(dt.ActiveGuild(), expr)
+		if err != nil {
+			return err
+		}
+		members, err := fetchMembers(dt)
+		if err != nil {
+			return err
+		}
+
+		// Only change members whose roles would actually change
+		targets := []*discordgo.Member{}
+		for _, m := range members {
+			if discordterm.HasRole(m, role.ID) != add && filter(m) {
+				targets = append(targets, m)
+			}
+		}
+		verb := "given"
+		if !add {
+			verb = "removed from"
+		}
+		if len(targets) == 0 {
+			fmt.Println("No members need to be changed")
+			return nil
+		}
+		fmt.Printf("The role %s will be %s %d members:\n", role.Name, verb, len(targets))
+		for i, m := range targets {
+			if i == previewLimit {
+				fmt.Printf("... and %d more\n", len(targets)-previewLimit)
+				break
+			}
+			printMemberLine(dt, m)
+		}
+		if flags.Has("dry-run") {
+			fmt.Println("Dry run, no changes were made")
+			return nil
+		}
+		if !confirm("Apply these changes?") {
+			fmt.Println("Cancelled")
+			return nil
+		}
+
+		failures := dt.ChangeMembersRole(dt.ActiveGuild(), role.ID, targets, add, os.Stdout)
+		fmt.Printf("Changed %d members, %d failed\n", len(targets)-len(failures), len(failures))
+		for _, f := range failures {
+			fmt.Println(" ", memberName(f.Member), f.Member.User.ID, "\t", f.Err)
+		}
+
+	// Create a channel
+	case "channel-create":
+		if dt.ActiveGuild() == "" {
+			return errors.New("You need to be in a guild to use this command")
+		}
+		rest, flags := parseFlags(args)
+		name := rest.After(1)
+		if name == "" {
+			return errors.New("Please provide a name for the channel")
+		}
+		data := discordgo.GuildChannelCreateData{Name: name}
+		if flags.Has("type") {
+			t, err := discordterm.ParseChannelType(flags.Get("type"))
+			if err != nil {
+				return err
+			}
+			data.Type = t
+		}
+		if flags.Has("category") {
+			category, err := dt.ResolveChannel(dt.ActiveGuild(), flags.Get("category"))
+			if err != nil {
+				return err
+			}
+			data.ParentID = category.ID
+		}
+		data.Topic = flags.Get("topic")
+		channel, err := dt.CreateChannel(dt.ActiveGuild(), data)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created %s channel %s (%s)\n", discordterm.ChannelTypeName(channel.Type), channel.Name, channel.ID)
+
+	// Rename a channel
+	case "channel-rename":
+		rest, flags := parseFlags(args)
+		channel, err := channelFlag(dt, flags)
+		if err != nil {
+			return err
+		}
+		name := rest.After(1)
+		if name == "" {
+			return errors.New("Please provide the new name of the channel")
+		}
+		old := channel.Name
+		channel, err = dt.EditChannel(channel.ID, &discordgo.ChannelEdit{Name: name})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Renamed %s to %s\n", old, channel.Name)
+
+	// Print or set the topic of the active channel
+	case "topic":
+		channel, err := channelFlag(dt, Flags{})
+		if err != nil {
+			return err
+		}
+		rest, flags := parseFlags(args, "clear")
+		if rest.After(1) == "" && !flags.Has("clear") {
+			if channel.Topic == "" {
+				fmt.Println("#" + channel.Name + " has no topic")
+			} else {
+				fmt.Println(channel.Topic)
+			}
+			return nil
+		}
+		topic := rest.After(1)
+		if topic == "" {
+			_, err = dt.RemoveTopic(channel.ID)
+		} else {
+			_, err = dt.EditChannel(channel.ID, &discordgo.ChannelEdit{Topic: topic})
+		}
+		if err != nil {
+			return err
+		}
+		if topic == "" {
+			fmt.Println("Removed the topic of #" + channel.Name)
+		} else {
+			fmt.Println("Set the topic of #" + channel.Name)
+		}
+
+	// Print or set the slowmode of the active channel
+	case "slowmode":
+		channel, err := channelFlag(dt, Flags{})
+		if err != nil {
+			return err
+		}
+		if args.Get(1) == "" {
+			if channel.RateLimitPerUser == 0 {
+				fmt.Println("Slowmode is off")
+			} else {
+				fmt.Println("Slowmode is", time.Duration(channel.RateLimitPerUser)*time.Second)
+			}
+			return nil
+		}
+		seconds, err := parseSlowmode(args.Get(1))
+		if err != nil {
+			return err
+		}
+		_, err = dt.EditChannel(channel.ID, &discordgo.ChannelEdit{RateLimitPerUser: &seconds})
+		if err != nil {
+			return err
+		}
+		if seconds == 0 {
+			fmt.Println("Turned slowmode off in #" + channel.Name)
+		} else {
+			fmt.Printf("Set slowmode in #%s to %s\n", channel.Name, time.Duration(seconds)*time.Second)
+		}
+
+	// Delete a channel
+	case "channel-delete":
+		var channel *discordgo.Channel
+		var err error
+		if args.After(1) != "" {
+			if dt.ActiveGuild() == "" {
+				return errors.New("You need to be in a guild to use this command")
+			}
+			channel, err = dt.ResolveChannel(dt.ActiveGuild(), args.After(1))
+		} else {
+			channel, err = channelFlag(dt, Flags{})
+		}
+		if err != nil {
+			return err
+		}
+		if !confirm(fmt.Sprintf("Delete the %s channel %s (%s)?", discordterm.ChannelTypeName(channel.Type), channel.Name, channel.ID)) {
+			fmt.Println("Cancelled")
+			return nil
+		}
+		err = dt.DeleteChannel(channel.ID)
+		if err != nil {
+			return err
+		}
+		if dt.ActiveChannel() == channel.ID {
+			dt.SetChannel("")
+		}
+		fmt.Println("Deleted channel", channel.Name)
+
+	// Change a permission overwrite
+	case "overwrite":
+		if dt.ActiveGuild() == "" {
+			return errors.New("You need to be in a guild to use this command")
+		}
+		if args.Get(3) == "" {
+			return errors.New("Usage: /overwrite [channel] [role|user] [allow|deny|inherit|clear] [perms]")
+		}
+		channel, err := channelFlag(dt, Flags{"channel": args.Get(1)})
+		if err != nil {
+			return err
+		}
+		targetID, targetType, targetName, err := dt.ResolveOverwriteTarget(dt.ActiveGuild(), args.Get(2))
+		if err != nil {
+			return err
+		}
+
+		var allow, deny, inherit int64
+		action := strings.ToLower(args.Get(3))
+		if action == "clear" {
+			inherit = discordterm.PermissionAllBits
+		} else {
+			perms, err := discordterm.ParsePermissions(args.After(4))
+			if err != nil {
+				return err
+			}
+			if perms == 0 {
+				return errors.New("Please provide the permissions to " + action)
+			}
+			switch action {
+			case "allow":
+				allow = perms
+			case "deny":
+				deny = perms
+			case "inherit":
+				inherit = perms
+			default:
+				return errors.New("The action must be allow, deny, inherit or clear")
+			}
+		}
+
+		o, err := dt.UpdateOverwrite(channel.ID, targetID, targetType, allow, deny, inherit)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Overwrite for %s in #%s:\n", targetName, channel.Name)
+		if o.Allow == 0 && o.Deny == 0 {
+			fmt.Println("    removed, permissions are inherited")
+			return nil
+		}
+		fmt.Println("    allow:", strings.Join(discordterm.PermissionNames(int64(o.Allow)), ", "))
+		fmt.Println("    deny: ", strings.Join(discordterm.PermissionNames(int64(o.Deny)), ", "))
+
+	// Kick a member
+	case "kick":
+		if dt.ActiveGuild() == "" {
+			return errors.New("You need to be in a guild to use this command")
+		}
+		rest, reason := parseReason(args)
+		if rest.After(1) == "" {
+			return errors.New("Please provide the member to kick")
+		}
+		user, err := dt.ResolveUser(dt.ActiveGuild(), rest.After(1))
+		if err != nil {
+			return err
+		}
+		if !confirm(fmt.Sprintf("Kick %s#%s (%s)?", user.Username, user.Discriminator, user.ID)) {
+			fmt.Println("Cancelled")
+			return nil
+		}
+		err = dt.Kick(dt.ActiveGuild(), user.ID, reason)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Kicked", user.Username)
+		printRecordedReason(dt, discordgo.AuditLogActionMemberKick, user.ID)
+
+	// Ban a user
+	case "ban":
+		if dt.ActiveGuild() == "" {
+			return errors.New("You need to be in a guild to use this command")
+		}
+		rest, reason := parseReason(args)
+		rest, flags := parseFlags(rest)
+		if rest.After(1) == "" {
+			return errors.New("Please provide the user to ban")
+		}
+		days := 0
+		if flags.Has("delete-days") {
+			n, err := strconv.Atoi(flags.Get("delete-days"))
+			if err != nil {
+				return err
+			}
+			days = n
+		}
+		user, err := dt.ResolveUser(dt.ActiveGuild(), rest.After(1))
+		if err != nil {
+			return err
+		}
+		if !confirm(fmt.Sprintf("Ban %s#%s (%s)?", user.Username, user.Discriminator, user.ID)) {
+			fmt.Println("Cancelled")
+			return nil
+		}
+		err = dt.Ban(dt.ActiveGuild(), user.ID, reason, days)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Banned", user.Username)
+		printRecordedReason(dt, discordgo.AuditLogActionMemberBanAdd, user.ID)
+
+	// Remove a ban
+	case "unban":
+		if dt.ActiveGuild() == "" {
+			return errors.New("You need to be in a guild to use this command")
+		}
+		rest, reason := parseReason(args)
+		if rest.After(1) == "" {
+			return errors.New("Please provide the user to unban")
+		}
+		ban, err := dt.FindBan(dt.ActiveGuild(), rest.After(1))
+		if err != nil {
+			return err
+		}
+		err = dt.Unban(dt.ActiveGuild(), ban.User.ID, reason)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Unbanned", ban.User.Username)
+		printRecordedReason(dt, discordgo.AuditLogActionMemberBanRemove, ban.User.ID)
+
+	// List the bans of the current guild
+	case "bans":
+		if dt.ActiveGuild() == "" {
+			return errors.New("You need to be in a guild to use this command")
+		}
+		bans, err := dt.Bans(dt.ActiveGuild(), args.Get(1), bansPageSize)
+		if err != nil {
+			return err
+		}
+		if len(bans) == 0 {
+			fmt.Println("No bans returned")
+			return nil
+		}
+		for _, b := range bans {
+			reason := b.Reason
+			if reason == "" {
+				reason = "no reason given"
+			}
+			name := b.User.Username + "#" + b.User.Discriminator
+			if dt.Conf.ColorText {
+				fmt.Println(Cyan(b.User.ID), "\t", Green(name), "\t", reason)
+			} else {
+				fmt.Println(b.User.ID, "\t", name, "\t", reason)
+			}
+		}
+		if len(bans) == bansPageSize {
+			fmt.Println("Use /bans " + bans[len(bans)-1].User.ID + " to list more bans")
+		}
+
+	// Time out a member
+	case "timeout":
+		if dt.ActiveGuild() == "" {
+			return errors.New("You need to be in a guild to use this command")
+		}
+		rest, reason := parseReason(args)
+		if rest.Get(2) == "" {
+			return errors.New("Please provide a member and a duration")
+		}
+		if len(rest) > 3 {
+			return errors.New("Too many arguments, the reason goes after --reason")
+		}
+		var d time.Duration
+		if !isOff(rest.Get(2)) {
+			var err error
+			d, err = discordterm.ParseDays(rest.Get(2))
+			if err != nil {
+				return err
+			}
+		}
+		user, err := dt.ResolveUser(dt.ActiveGuild(), rest.Get(1))
+		if err != nil {
+			return err
+		}
+		if d != 0 && !confirm(fmt.Sprintf("Time out %s#%s (%s) for %s?", user.Username, user.Discriminator, user.ID, rest.Get(2))) {
+			fmt.Println("Cancelled")
+			return nil
+		}
+		until, err := dt.Timeout(dt.ActiveGuild(), user.ID, d, reason)
+		if err != nil {
+			return err
+		}
+		if d == 0 {
+			fmt.Println("Removed the timeout of", user.Username)
+		} else {
+			fmt.Printf("Timed out %s until %s\n", user.Username, until.Local().Format("2006-01-02 15:04:05"))
+		}
+		printRecordedReason(dt, discordgo.AuditLogActionMemberUpdate, user.ID)
+
+	// Print the audit log
+	case "audit":
+		if dt.ActiveGuild() == "" {
+			return errors.New("You need to be in a guild to use this command")
+		}
+		_, flags := parseFlags(args, "json")
+		limit := 25
+		if flags.Has("limit") {
+			n, err := strconv.Atoi(flags.Get("limit"))
+			if err != nil || n < 1 {
+				return errors.New("The limit must be a positive number")
+			}
+			limit = n
+		}
+		var action discordgo.AuditLogAction
+		if flags.Has("action") {
+			a, err := discordterm.ParseAuditAction(flags.Get("action"))
+			if err != nil {
+				return err
+			}
+			action = a
+		}
+		userID := ""
+		if flags.Has("user") {
+			user, err := dt.ResolveUser(dt.ActiveGuild(), flags.Get("user"))
+			if err != nil {
+				return err
+			}
+			userID = user.ID
+		}
+
+		auditLog, err := dt.AuditLog(dt.ActiveGuild(), userID, action, limit)
+		if err != nil {
+			return err
+		}
+		records := dt.AuditRecords(dt.ActiveGuild(), auditLog)
+		if !flags.Has("json") && !flags.Has("out") {
+			if len(records) == 0 {
+				fmt.Println("No audit log entries found")
+			}
+			dt.PrintAuditRecords(records, dt.Conf)
+			return nil
+		}
+
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return err
+		}
+		if flags.Get("out") == "" {
+			fmt.Println(string(data))
+			return nil
+		}
+		err = ioutil.WriteFile(flags.Get("out"), data, 0644)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %d audit log entries to %s\n", len(records), flags.Get("out"))
+
+	// Create an invite to the active channel
+	case "invite-create":
+		if dt.ActiveChannel() == "" {
+			return errors.New("You need to be in a channel to use this command")
+		}
+		_, flags := parseFlags(args, "temporary")
+		maxAge := 24 * time.Hour
+		if flags.Has("max-age") {
+			if v := flags.Get("max-age"); v == "never" || v == "0" {
+				maxAge = 0
+			} else {
+				d, err := discordterm.ParseDays(v)
+				if err != nil {
+					return err
+				}
+				maxAge = d
+			}
+		}
+		maxUses := 0
+		if flags.Has("max-uses") {
+			n, err := strconv.Atoi(flags.Get("max-uses"))
+			if err != nil {
+				return err
+			}
+			maxUses = n
+		}
+		invite, err := dt.CreateInvite(dt.ActiveChannel(), maxAge, maxUses, flags.Has("temporary"))
+		if err != nil {
+			return err
+		}
+		fmt.Println(discordterm.InviteURL(invite.Code))
+		fmt.Println("Expires:", formatInviteExpiry(invite))
+
+	// List the invites of the current guild
+	case "invites":
+		if dt.ActiveGuild() == "" {
+			return errors.New("You need to be in a guild to use this command")
+		}
+		invites, err := dt.GuildInvites(dt.ActiveGuild())
+		if err != nil {
+			return err
+		}
+		if len(invites) == 0 {
+			fmt.Println("The guild has no invites")
+			return nil
+		}
+		for _, inv := range invites {
+			uses := strconv.Itoa(inv.Uses)
+			if inv.MaxUses > 0 {
+				uses += "/" + strconv.Itoa(inv.MaxUses)
+			}
+			inviter := "unknown"
+			if inv.Inviter != nil {
+				inviter = inv.Inviter.Username
+			}
+			channel := ""
+			if inv.Channel != nil {
+				channel = "#" + inv.Channel.Name
+			}
+			info := fmt.Sprintf("%s uses, by %s, expires %s", uses, inviter, formatInviteExpiry(inv))
+			if inv.Temporary {
+				info += ", temporary"
+			}
+			if dt.Conf.ColorText {
+				fmt.Println(Cyan(inv.Code), "\t", Green(channel), info)
+			} else {
+				fmt.Println(inv.Code, "\t", channel, info)
+			}
+		}
+
+	// Revoke an invite
+	case "invite-revoke":
+		if args.Get(1) == "" {
+			return errors.New("Please provide an invite code")
+		}
+		invite, err := dt.RevokeInvite(args.Get(1))
+		if err != nil {
+			return err
+		}
+		fmt.Println("Revoked invite", invite.Code)
+
+	// Print what an invite leads to
+	case "invite-info":
+		if args.Get(1) == "" {
+			return errors.New("Please provide an invite code")
+		}
+		invite, err := dt.InviteInfo(args.Get(1))
+		if err != nil {
+			return err
+		}
+		printInvite(dt, invite)
+
+	// Save the current guild to a backup file
+	case "guild-backup":
+		if dt.ActiveGuild() == "" {
+			return errors.New("You need to be in a guild to use this command")
+		}
+		path := args.After(1)
+		if path == "" {
+			guild, err := dt.Cli.State.Guild(dt.ActiveGuild())
+			if err != nil {
+				return err
+			}
+			path = discordterm.SanitizeFilename(guild.Name) + "-" + time.Now().Format("2006-01-02") + ".json"
+		}
+		backup, err := dt.BackupGuild(dt.ActiveGuild(), os.Stdout)
+		if err != nil {
+			return err
 		}
+		err = backup.Write(path)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Saved %d roles, %d channels and %d emojis to %s\n",
+			len(backup.Roles), len(backup.Channels), len(backup.Emojis), path)
 
-		guild, err := dt.Cli.State.Guild(guildID)
+	// Restore a backup into the current guild
+	case "guild-restore":
+		if dt.ActiveGuild() == "" {
+			return errors.New("You need to be in a guild to use this command")
+		}
+		rest, flags := parseFlags(args, "dry-run")
+		if rest.After(1) == "" {
+			return errors.New("Please provide a backup file")
+		}
+		backup, err := discordterm.ReadBackup(rest.After(1))
 		if err != nil {
 			return err
 		}
-		if len(guild.Roles) == 0 {
-			return errors.New("No roles found")
+		plan, err := dt.PlanRestore(backup, dt.ActiveGuild())
+		if err != nil {
+			return err
 		}
-		sort.Sort(discordgo.Roles(guild.Roles))
-		for _, role := range guild.Roles {
-			if dt.Conf.ColorText {
-				fmt.Println(Cyan(role.ID), "\t", Green(role.Name))
-			} else {
-				fmt.Println(role.ID, "\t", role.Name)
-			}
+		for _, note := range plan.Notes {
+			fmt.Println("Note:", note)
+		}
+		if len(plan.Steps) == 0 {
+			fmt.Println("The guild already matches the backup")
+			return nil
+		}
+		fmt.Printf("Restoring %s from %s will make %d changes:\n",
+			backup.Guild.Name, backup.CreatedAt.Local().Format("2006-01-02 15:04"), len(plan.Steps))
+		for _, step := range plan.Steps {
+			fmt.Println(" ", step.Description)
+		}
+		if flags.Has("dry-run") {
+			fmt.Println("Dry run, no changes were made")
+			return nil
+		}
+		if !confirm("Apply these changes?") {
+			fmt.Println("Cancelled")
+			return nil
+		}
+
+		failures := dt.ApplyRestore(plan, os.Stdout)
+		fmt.Printf("Made %d changes, %d failed\n", len(plan.Steps)-len(failures), len(failures))
+		for _, err := range failures {
+			fmt.Println("  failed:", err)
 		}
 
 	// Prints various information about a member. Like their nickname and roles
@@ -933,12 +2579,92 @@ func executeCommand(dt *discordterm.Client, line string) error {
 			dt.Conf.ShowNicknames = false
 		}
 
+	case "show-edits":
+		if args.Get(1) == "" {
+			fmt.Println(formatBoolOnOff(dt.Conf.ShowEdits))
+			return nil
+		}
+		if isOn(args.Get(1)) {
+			fmt.Println("Message edits will be displayed")
+			dt.Conf.ShowEdits = true
+		} else if isOff(args.Get(1)) {
+			fmt.Println("Message edits will not be displayed")
+			dt.Conf.ShowEdits = false
+		}
+
+	case "show-deletes":
+		if args.Get(1) == "" {
+			fmt.Println(formatBoolOnOff(dt.Conf.ShowDeletes))
+			return nil
+		}
+		if isOn(args.Get(1)) {
+			fmt.Println("Message deletions will be displayed")
+			dt.Conf.ShowDeletes = true
+		} else if isOff(args.Get(1)) {
+			fmt.Println("Message deletions will not be displayed")
+			dt.Conf.ShowDeletes = false
+		}
+
+	case "show-hidden":
+		if args.Get(1) == "" {
+			fmt.Println(formatBoolOnOff(dt.Conf.ShowHiddenChannels))
+			return nil
+		}
+		if isOn(args.Get(1)) {
+			fmt.Println("Channels you can not view will be listed")
+			dt.Conf.ShowHiddenChannels = true
+		} else if isOff(args.Get(1)) {
+			fmt.Println("Channels you can not view will be hidden")
+			dt.Conf.ShowHiddenChannels = false
+		}
+
+	case "show-voice":
+		if args.Get(1) == "" {
+			fmt.Println(formatBoolOnOff(dt.Conf.ShowVoiceActivity))
+			return nil
+		}
+		if isOn(args.Get(1)) {
+			fmt.Println("Voice channel activity will be displayed")
+			dt.Conf.ShowVoiceActivity = true
+		} else if isOff(args.Get(1)) {
+			fmt.Println("Voice channel activity will not be displayed")
+			dt.Conf.ShowVoiceActivity = false
+		}
+
+	// Print the effective permissions of a member
+	case "perms", "permissions":
+		if dt.ActiveGuild() == "" {
+			return errors.New("You need to select a guild first")
+		}
+		userID := dt.Cli.State.User.ID
+		if args.Get(1) != "" {
+			member, err := dt.ResolveMember(dt.ActiveGuild(), args.Get(1))
+			if err != nil {
+				return err
+			}
+			userID = member.User.ID
+		}
+		channelID := dt.ActiveChannel()
+		if args.Get(2) != "" {
+			channel, err := dt.ResolveChannel(dt.ActiveGuild(), args.Get(2))
+			if err != nil {
+				return err
+			}
+			channelID = channel.ID
+		}
+		if channelID != "" {
+			if channel, err := dt.Cli.State.Channel(channelID); err != nil || channel.GuildID != dt.ActiveGuild() {
+				channelID = ""
+			}
+		}
+		return printPermissions(dt, userID, channelID)
+
 	// Change your username
 	case "username":
 		if args.Get(1) == "" {
 			return errors.New("Please enter the username you wish to use as an argument")
 		}
-		_, err := dt.Cli.UserUpdate(*username, *password, args.Get(1), dt.Cli.State.User.Avatar, "")
+		_, err := dt.Cli.UserUpdate(args.Get(1), dt.Cli.State.User.Avatar, "")
 		if err != nil {
 			return err
 		}
@@ -948,18 +2674,14 @@ func executeCommand(dt *discordterm.Client, line string) error {
 		if args.Get(1) == "" {
 			return errors.New("Please enter a status to switch to")
 		}
-		_, err := dt.Cli.UserUpdateStatus(discordgo.Status(args.Get(1)))
+		err := dt.Cli.UpdateStatusComplex(discordgo.UpdateStatusData{Status: args.Get(1)})
 		if err != nil {
 			return err
 		}
 
 	// Update your playing status
 	case "playing":
-		err := dt.Cli.UpdateStatusComplex(discordgo.UpdateStatusData{
-			Game: &discordgo.Game{
-				Name: args.After(1),
-			},
-		})
+		err := dt.Cli.UpdateGameStatus(0, args.After(1))
 		if err != nil {
 			return err
 		}
@@ -973,7 +2695,7 @@ func executeCommand(dt *discordterm.Client, line string) error {
 		fmt.Println("streaming status set to ", args.Get(1), " : ", args.Get(2))
 
 	case "playing-off":
-		err := dt.Cli.UpdateStatus(1, "")
+		err := dt.Cli.UpdateGameStatus(0, "")
 		if err != nil {
 			return err
 		}
@@ -995,7 +2717,7 @@ func executeCommand(dt *discordterm.Client, line string) error {
 		if dt.ActiveChannel() == "" {
 			return errors.New("You are not currently in a channel")
 		}
-		_, err := dt.Cli.ChannelMessageSend(dt.ActiveChannel(), args.After(1))
+		err := sendMessage(dt, args.After(1))
 		if err != nil {
 			return err
 		}
@@ -1028,6 +2750,421 @@ func executeCommand(dt *discordterm.Client, line string) error {
 	return nil
 }
 
+// findMessage finds a message in the active channel's last 100 messages
+// Whose ID contains the given substring
+func findMessage(dt *discordterm.Client, id string) (*discordgo.Message, error) {
+	messages, err := dt.Cli.ChannelMessages(dt.ActiveChannel(), 100, "", "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	var m *discordgo.Message
+	for _, message := range messages {
+		if strings.Contains(message.ID, id) {
+			m = message
+		}
+	}
+	if m == nil {
+		return nil, errors.New("Message ID was not in the past 100 messages, or did not contain the given substring")
+	}
+	return m, nil
+}
+
+// sendMessage sends a message to the active channel. Messages that are too
+// Long are split or uploaded as a file according to the overflow mode,
+// Asking the user which to do when the mode is "ask"
+func sendMessage(dt *discordterm.Client, content string) error {
+	return sendMessageComplex(dt, &discordgo.MessageSend{Content: content})
+}
+
+// sendMessageComplex is sendMessage for messages with options such as replies
+func sendMessageComplex(dt *discordterm.Client, data *discordgo.MessageSend) error {
+	content := data.Content
+	mode := dt.Conf.OverflowMode
+	if utf8.RuneCountInString(content) > discordterm.MaxMessageLength && (mode == discordterm.OverflowAsk || mode == "") {
+		parts := discordterm.SplitMessage(content, discordterm.MaxMessageLength)
+		answer := QueryInputString(bufio.NewReader(os.Stdin), fmt.Sprintf(
+			"Your message is %d characters long. (s)plit it into %d messages, (u)pload it as a file or (c)ancel?",
+			utf8.RuneCountInString(content), len(parts),
+		))
+		switch strings.ToLower(answer) {
+		case "s", "split":
+			mode = discordterm.OverflowSplit
+		case "u", "upload":
+			mode = discordterm.OverflowUpload
+		default:
+			fmt.Println("Message cancelled")
+			return nil
+		}
+	}
+	return dt.SendLongMessageComplex(dt.ActiveChannel(), data, mode)
+}
+
+// printSaved prints where an attachment was saved
+func printSaved(dt *discordterm.Client, path string, existed bool) {
+	msg := "Saved to"
+	if existed {
+		msg = "Already saved at"
+	}
+	if dt.Conf.ColorText {
+		fmt.Println(msg, Green(path))
+	} else {
+		fmt.Println(msg, path)
+	}
+}
+
+// printUploadResult prints whether a file was uploaded successfully
+func printUploadResult(dt *discordterm.Client, u *discordterm.Upload, err error) {
+	if err != nil {
+		if dt.Conf.ColorText {
+			fmt.Println(Red("Failed"), u.Path, "\t", Red(err))
+		} else {
+			fmt.Println("Failed", u.Path, "\t", err)
+		}
+		return
+	}
+	if dt.Conf.ColorText {
+		fmt.Println(Green("Uploaded"), u.Path, "\t", discordterm.FormatBytes(u.Size))
+	} else {
+		fmt.Println("Uploaded", u.Path, "\t", discordterm.FormatBytes(u.Size))
+	}
+}
+
+// printPermissions prints the permission breakdown of a member,
+// One permission per line
+func printPermissions(dt *discordterm.Client, userID, channelID string) error {
+	perms, err := dt.ComputePermissions(dt.ActiveGuild(), userID, channelID)
+	if err != nil {
+		return err
+	}
+
+	header := "Permissions of " + userID
+	if member, err := dt.Member(dt.ActiveGuild(), userID); err == nil {
+		header = "Permissions of " + member.User.Username + "#" + member.User.Discriminator
+	}
+	if channel, err := dt.Cli.State.Channel(channelID); err == nil {
+		header += " in #" + channel.Name
+	}
+	fmt.Println(header, fmt.Sprintf("(%d)", perms.Permissions))
+
+	for _, p := range perms.States {
+		name := fmt.Sprintf("%-26s", p.Name)
+		if dt.Conf.ColorText {
+			if p.Allowed {
+				fmt.Println(" ", Green("✓"), name, Gray(p.Reason))
+			} else {
+				fmt.Println(" ", Red("✗"), name, Gray(p.Reason))
+			}
+		} else {
+			mark := "✗"
+			if p.Allowed {
+				mark = "✓"
+			}
+			fmt.Println(" ", mark, name, p.Reason)
+		}
+	}
+	return nil
+}
+
+// printVoiceMembers prints the users connected to a voice channel with their voice flags
+func printVoiceMembers(dt *discordterm.Client, guildID, channelID, indent string) {
+	for _, vm := range dt.VoiceMembers(guildID, channelID) {
+		flags := ""
+		if f := vm.Flags(); len(f) > 0 {
+			flags = "(" + strings.Join(f, ", ") + ")"
+		}
+		if dt.Conf.ColorText {
+			fmt.Println(" ", "\t", indent+Cyan(vm.Name).String(), Gray(flags))
+		} else {
+			fmt.Println(" ", "\t", indent+vm.Name, flags)
+		}
+	}
+}
+
+// roleParams reads the --color, --hoist, --mentionable and --perms flags of the role commands.
+// Permissions are applied to current.
+func roleParams(flags Flags, current int64) (*discordgo.RoleParams, error) {
+	params := &discordgo.RoleParams{}
+	if flags.Has("color") {
+		color, err := discordterm.ParseColor(flags.Get("color"))
+		if err != nil {
+			return nil, err
+		}
+		params.Color = &color
+	}
+	for _, name := range []string{"hoist", "mentionable"} {
+		if !flags.Has(name) {
+			continue
+		}
+		// --hoist is the same as --hoist=on
+		var value bool
+		switch v := flags.Get(name); {
+		case v == "" || isOn(v) || v == "true":
+			value = true
+		case isOff(v) || v == "false":
+			value = false
+		default:
+			return nil, errors.New("--" + name + " takes on or off, as in --" + name + "=off")
+		}
+		if name == "hoist" {
+			params.Hoist = &value
+		} else {
+			params.Mentionable = &value
+		}
+	}
+	if flags.Has("perms") {
+		perms, err := discordterm.ApplyPermissions(current, flags.Get("perms"))
+		if err != nil {
+			return nil, err
+		}
+		params.Permissions = &perms
+	}
+	return params, nil
+}
+
+// rolePosition returns the position to move a role to from the
+// --position, --above and --below flags
+func rolePosition(dt *discordterm.Client, role *discordgo.Role, flags Flags) (int, error) {
+	if flags.Has("position") {
+		return strconv.Atoi(flags.Get("position"))
+	}
+
+	target := flags.Get("above")
+	if target == "" {
+		target = flags.Get("below")
+	}
+	if target == "" {
+		return 0, errors.New("Please provide --position, --above or --below")
+	}
+	other, err := dt.ResolveRole(dt.ActiveGuild(), target)
+	if err != nil {
+		return 0, err
+	}
+	if other.ID == role.ID {
+		return 0, errors.New("A role can not be moved relative to itself")
+	}
+	if other.ID == dt.ActiveGuild() {
+		// Every role is above @everyone
+		return 1, nil
+	}
+
+	// Positions count up from the lowest role, leaving out @everyone and the moved role
+	roles, err := dt.GuildRoles(dt.ActiveGuild())
+	if err != nil {
+		return 0, err
+	}
+	position := 0
+	for i := len(roles) - 1; i >= 0; i-- {
+		r := roles[i]
+		if r.ID == dt.ActiveGuild() || r.ID == role.ID {
+			continue
+		}
+		position++
+		if r.ID == other.ID {
+			break
+		}
+	}
+	if flags.Has("above") {
+		return position + 1, nil
+	}
+	return position, nil
+}
+
+// channelFlag returns the channel given by the --channel flag, or the active channel.
+// "here" is the active channel.
+func channelFlag(dt *discordterm.Client, flags Flags) (*discordgo.Channel, error) {
+	if name := flags.Get("channel"); name != "" && name != "here" {
+		if dt.ActiveGuild() == "" {
+			return nil, errors.New("You need to be in a guild to use this command")
+		}
+		return dt.ResolveChannel(dt.ActiveGuild(), name)
+	}
+	if dt.ActiveChannel() == "" {
+		return nil, errors.New("You need to select a channel first")
+	}
+	return dt.Channel(dt.ActiveChannel())
+}
+
+// parseSlowmode parses a slowmode given in seconds, as a duration such as 5m, or "off"
+func parseSlowmode(s string) (int, error) {
+	if isOff(s) {
+		return 0, nil
+	}
+	seconds, err := strconv.Atoi(s)
+	if err != nil {
+		d, err := discordterm.ParseDays(s)
+		if err != nil {
+			return 0, errors.New("Slowmode must be a number of seconds or a duration such as 5m")
+		}
+		seconds = int(d.Seconds())
+	}
+	if seconds < 0 || seconds > discordterm.MaxSlowmode {
+		return 0, fmt.Errorf("Slowmode must be between 0 and %d seconds", discordterm.MaxSlowmode)
+	}
+	return seconds, nil
+}
+
+// formatInviteExpiry formats when an invite expires
+func formatInviteExpiry(inv *discordgo.Invite) string {
+	expiry, ok := discordterm.InviteExpiry(inv)
+	if !ok {
+		return "never"
+	}
+	if time.Now().After(expiry) {
+		return "expired"
+	}
+	return "in " + discordterm.FormatAge(time.Until(expiry))
+}
+
+// printInvite prints the guild and channel an invite leads to
+func printInvite(dt *discordterm.Client, inv *discordgo.Invite) {
+	field := func(label string, value interface{}) {
+		if dt.Conf.ColorText {
+			fmt.Println(Cyan(label+":"), value)
+		} else {
+			fmt.Println(label+":", value)
+		}
+	}
+	field("Invite", discordterm.InviteURL(inv.Code))
+	if inv.Guild != nil {
+		field("Guild", inv.Guild.Name+" ("+inv.Guild.ID+")")
+		if inv.Guild.Description != "" {
+			field("Description", inv.Guild.Description)
+		}
+		field("Members", fmt.Sprintf("%d (%d online)", inv.ApproximateMemberCount, inv.ApproximatePresenceCount))
+	}
+	if inv.Channel != nil {
+		field("Channel", "#"+inv.Channel.Name+" ("+discordterm.ChannelTypeName(inv.Channel.Type)+", "+inv.Channel.ID+")")
+	}
+	if inv.Inviter != nil {
+		field("Inviter", inv.Inviter.Username+"#"+inv.Inviter.Discriminator)
+	}
+	if inv.MaxAge > 0 {
+		field("Expires", formatInviteExpiry(inv))
+	}
+}
+
+// bansPageSize is the number of bans listed by /bans at a time
+const bansPageSize = 50
+
+// printRecordedReason prints the reason the audit log recorded for a moderation action
+func printRecordedReason(dt *discordterm.Client, action discordgo.AuditLogAction, targetID string) {
+	reason, err := dt.RecordedReason(dt.ActiveGuild(), action, targetID)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if reason == "" {
+		reason = "no reason was recorded"
+	}
+	if dt.Conf.ColorText {
+		fmt.Println(Gray("Audit log reason:"), reason)
+	} else {
+		fmt.Println("Audit log reason:", reason)
+	}
+}
+
+// previewLimit is the number of members listed before a bulk change
+const previewLimit = 20
+
+// fetchMembers fetches every member of the active guild, printing the count as pages arrive
+func fetchMembers(dt *discordterm.Client) ([]*discordgo.Member, error) {
+	members, err := dt.AllMembers(dt.ActiveGuild(), func(n int) {
+		fmt.Printf("\rFetched %d members", n)
+	})
+	fmt.Println()
+	return members, err
+}
+
+// memberName returns a member's username and discriminator, and their nickname if they have one
+func memberName(m *discordgo.Member) string {
+	name := m.User.Username + "#" + m.User.Discriminator
+	if m.Nick != "" {
+		name += " (" + m.Nick + ")"
+	}
+	return name
+}
+
+// printMemberLine prints a member's ID and name on one line
+func printMemberLine(dt *discordterm.Client, m *discordgo.Member) {
+	if dt.Conf.ColorText {
+		fmt.Println(Cyan(m.User.ID), "\t", Green(memberName(m)))
+	} else {
+		fmt.Println(m.User.ID, "\t", memberName(m))
+	}
+}
+
+// printSelectedChannel tells the user which channel was selected
+func printSelectedChannel(channel *discordgo.Channel) {
+	fmt.Printf("Selected channel: %s\n", channel.Name)
+	if discordterm.IsForumType(channel.Type) {
+		fmt.Println("This channel is a forum. Use /posts to list its posts and /post-new to create one")
+	}
+}
+
+// activeForum returns the active forum, or the forum of the active post
+func activeForum(dt *discordterm.Client) (*discordterm.Forum, error) {
+	if dt.ActiveChannel() == "" {
+		return nil, errors.New("You need to select a forum first")
+	}
+	channel, err := dt.Channel(dt.ActiveChannel())
+	if err != nil {
+		return nil, err
+	}
+	if discordterm.IsThreadType(channel.Type) {
+		channel, err = dt.Channel(channel.ParentID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !discordterm.IsForumType(channel.Type) {
+		return nil, errors.New("The active channel is not a forum")
+	}
+	return dt.Forum(channel.ID)
+}
+
+// containsString returns true if s is in list
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveMessageID returns id if it is a complete message ID, otherwise
+// The ID of the recent message in the active channel containing it
+func resolveMessageID(dt *discordterm.Client, id string) (string, error) {
+	if discordterm.IsSnowflake(id) {
+		return id, nil
+	}
+	m, err := findMessage(dt, id)
+	if err != nil {
+		return "", err
+	}
+	return m.ID, nil
+}
+
+// messageImageURLs returns the URLs of a message's image attachments and embed images
+func messageImageURLs(m *discordgo.Message) []string {
+	urls := []string{}
+	for _, a := range m.Attachments {
+		if discordterm.IsImageAttachment(a) {
+			urls = append(urls, a.URL)
+		}
+	}
+	for _, em := range m.Embeds {
+		if em.Image != nil && em.Image.URL != "" {
+			urls = append(urls, em.Image.URL)
+		}
+		if em.Thumbnail != nil && em.Thumbnail.URL != "" {
+			urls = append(urls, em.Thumbnail.URL)
+		}
+	}
+	return urls
+}
+
 // Must ...
 func Must(err error) {
 	if err != nil {
@@ -1042,6 +3179,16 @@ func ReadInputString(rd *bufio.Reader) string {
 	return line
 }
 
+// confirm asks the user a yes or no question, defaulting to no
+func confirm(query string) bool {
+	answer := QueryInputString(bufio.NewReader(os.Stdin), query+" [y/N]")
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
 // QueryInputString queries for a string of input
 func QueryInputString(rd *bufio.Reader, query string) string {
 	fmt.Println(query)
@@ -1049,6 +3196,28 @@ func QueryInputString(rd *bufio.Reader, query string) string {
 	return strings.TrimSpace(ReadInputString(rd))
 }
 
+// login requests a user token with an email and password,
+// Which discordgo no longer does
+func login(s *discordgo.Session, email, password string) (string, error) {
+	body, err := s.RequestWithBucketID("POST", discordgo.EndpointAPI+"auth/login", map[string]string{
+		"login":    email,
+		"password": password,
+	}, discordgo.EndpointAPI+"auth/login")
+	if err != nil {
+		return "", err
+	}
+	var resp struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", err
+	}
+	if resp.Token == "" {
+		return "", errors.New("Could not log in, discord did not return a token")
+	}
+	return resp.Token, nil
+}
+
 // GetLoginInfoFromInput ...
 func GetLoginInfoFromInput() {
 	rd := bufio.NewReader(os.Stdin)
@@ -1130,16 +3299,36 @@ func main() {
 		GetLoginInfoFromInput()
 	}
 
-	session, err := discordgo.New(*username, *password, *token)
+	session, err := discordgo.New(*token)
 	if err != nil {
 		log.Fatal(err)
 	}
+	if *token == "" {
+		t, err := login(session, *username, *password)
+		if err != nil {
+			log.Fatal(err)
+		}
+		session.Token = t
+		session.Identify.Token = t
+	}
+	// Message contents and members are privileged intents
+	// That bots need to have enabled in the developer portal
+	session.Identify.Intents = discordgo.IntentsAllWithoutPrivileged | discordgo.IntentsGuildMembers | discordgo.IntentsMessageContent
 	dt := discordterm.NewClient(session, &discordterm.Config{
 		ShowImages:    *showImages,
 		ImageWidth:    *imageWidth,
 		ColorText:     *colorText,
 		ColorImages:   *colorImages,
 		ShowNicknames: *showNicknames,
+
+		AutoPlay:       *autoPlay,
+		AnimationLoops: *imageLoops,
+		OverflowMode:   *overflowMode,
+		ShowEdits:      *showEdits,
+		ShowDeletes:    *showDeletes,
+
+		ShowHiddenChannels: *showHidden,
+		ShowVoiceActivity:  *showVoice,
 	})
 
 	ready := make(chan bool)
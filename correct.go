@@ -0,0 +1,190 @@
+package discordterm
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxSentMessages is the number of sent messages remembered per channel
+const maxSentMessages = 50
+
+// Substitution is an IRC style s/pattern/replacement/flags correction
+type Substitution struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+	Global      bool
+}
+
+// IsSubstitution reports whether a line of input looks like a substitution
+func IsSubstitution(line string) bool {
+	return strings.HasPrefix(line, "s/") && strings.Count(line, "/") >= 2
+}
+
+// ParseSubstitution parses an expression of the form s/pattern/replacement/[flags].
+// The pattern is a regular expression, and the delimiter can be escaped with a backslash.
+// Supported flags are g to replace every match and i to ignore case.
+func ParseSubstitution(expr string) (*Substitution, error) {
+	if !strings.HasPrefix(expr, "s/") {
+		return nil, errors.New("substitution must begin with s/")
+	}
+
+	fields := []string{}
+	var cur strings.Builder
+	escaped := false
+	for _, r := range expr[2:] {
+		switch {
+		case escaped:
+			if r != '/' {
+				cur.WriteRune('\\')
+			}
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '/':
+			fields = append(fields, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if escaped {
+		cur.WriteRune('\\')
+	}
+	fields = append(fields, cur.String())
+
+	if len(fields) < 2 || len(fields) > 3 {
+		return nil, errors.New("substitution must look like s/pattern/replacement/[flags]")
+	}
+	if fields[0] == "" {
+		return nil, errors.New("substitution pattern is empty")
+	}
+
+	sub := &Substitution{Replacement: fields[1]}
+	pattern := fields[0]
+	if len(fields) == 3 {
+		for _, f := range fields[2] {
+			switch f {
+			case 'g':
+				sub.Global = true
+			case 'i':
+				pattern = "(?i)" + pattern
+			default:
+				return nil, errors.New("unknown substitution flag " + string(f))
+			}
+		}
+	}
+
+	var err error
+	sub.Pattern, err = regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// Apply applies the substitution to text
+func (s *Substitution) Apply(text string) string {
+	if s.Global {
+		return s.Pattern.ReplaceAllString(text, s.Replacement)
+	}
+
+	loc := s.Pattern.FindStringSubmatchIndex(text)
+	if loc == nil {
+		return text
+	}
+	var dst []byte
+	dst = s.Pattern.ExpandString(dst, s.Replacement, text, loc)
+	return text[:loc[0]] + string(dst) + text[loc[1]:]
+}
+
+// TrackSentMessage remembers a message sent by the client's user
+func (c *Client) TrackSentMessage(channelID, messageID string) {
+	c.Lock()
+	defer c.Unlock()
+
+	ids := append(c.sentMessages[channelID], messageID)
+	if len(ids) > maxSentMessages {
+		ids = ids[len(ids)-maxSentMessages:]
+	}
+	c.sentMessages[channelID] = ids
+}
+
+// ForgetSentMessage forgets a sent message, for example after it is deleted
+func (c *Client) ForgetSentMessage(channelID, messageID string) {
+	c.Lock()
+	defer c.Unlock()
+
+	ids := c.sentMessages[channelID]
+	for i, id := range ids {
+		if id == messageID {
+			c.sentMessages[channelID] = append(ids[:i:i], ids[i+1:]...)
+			return
+		}
+	}
+}
+
+// LastSentMessage returns the most recent message the client's user sent in a channel.
+// If no message was tracked since the client started, the channel history is searched.
+func (c *Client) LastSentMessage(channelID string) (*discordgo.Message, error) {
+	c.Lock()
+	ids := c.sentMessages[channelID]
+	var id string
+	if len(ids) > 0 {
+		id = ids[len(ids)-1]
+	}
+	c.Unlock()
+
+	if id != "" {
+		if m, err := c.Cli.State.Message(channelID, id); err == nil {
+			return m, nil
+		}
+		return c.Cli.ChannelMessage(channelID, id)
+	}
+
+	messages, err := c.Cli.ChannelMessages(channelID, 100, "", "", "")
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range messages {
+		if m.Author != nil && m.Author.ID == c.Cli.State.User.ID {
+			return m, nil
+		}
+	}
+	return nil, errors.New("you have not sent any messages in this channel recently")
+}
+
+// CorrectLastMessage applies a substitution to the client user's last message in a channel
+func (c *Client) CorrectLastMessage(channelID, expr string) (*discordgo.Message, error) {
+	sub, err := ParseSubstitution(expr)
+	if err != nil {
+		return nil, err
+	}
+	m, err := c.LastSentMessage(channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	content := sub.Apply(m.Content)
+	if content == m.Content {
+		return nil, errors.New("pattern did not match your last message")
+	}
+	return c.Cli.ChannelMessageEdit(channelID, m.ID, content)
+}
+
+// UndoLastMessage deletes the client user's last message in a channel
+func (c *Client) UndoLastMessage(channelID string) (*discordgo.Message, error) {
+	m, err := c.LastSentMessage(channelID)
+	if err != nil {
+		return nil, err
+	}
+	err = c.Cli.ChannelMessageDelete(channelID, m.ID)
+	if err != nil {
+		return nil, err
+	}
+	c.ForgetSentMessage(channelID, m.ID)
+	return m, nil
+}
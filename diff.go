@@ -0,0 +1,98 @@
+package discordterm
+
+import (
+	"strings"
+
+	. "github.com/logrusorgru/aurora"
+)
+
+// Kinds of word diff operations
+const (
+	DiffEqual = iota
+	DiffInsert
+	DiffDelete
+)
+
+// DiffOp is a run of words that were kept, inserted or deleted
+type DiffOp struct {
+	Kind  int
+	Words []string
+}
+
+// WordDiff computes a word level diff between two strings
+func WordDiff(before, after string) []DiffOp {
+	a := strings.Fields(before)
+	b := strings.Fields(after)
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = maxInt(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := []DiffOp{}
+	add := func(kind int, word string) {
+		if n := len(ops); n > 0 && ops[n-1].Kind == kind {
+			ops[n-1].Words = append(ops[n-1].Words, word)
+			return
+		}
+		ops = append(ops, DiffOp{kind, []string{word}})
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			add(DiffEqual, a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			add(DiffDelete, a[i])
+			i++
+		default:
+			add(DiffInsert, b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		add(DiffDelete, a[i])
+	}
+	for ; j < len(b); j++ {
+		add(DiffInsert, b[j])
+	}
+	return ops
+}
+
+// FormatWordDiff formats a word diff on a single line.
+// Without color, deletions are shown as [-words-] and insertions as {+words+}
+func FormatWordDiff(ops []DiffOp, color bool) string {
+	parts := make([]string, 0, len(ops))
+	for _, op := range ops {
+		text := strings.Join(op.Words, " ")
+		switch op.Kind {
+		case DiffDelete:
+			if color {
+				text = Red("[-" + text + "-]").String()
+			} else {
+				text = "[-" + text + "-]"
+			}
+		case DiffInsert:
+			if color {
+				text = Green("{+" + text + "+}").String()
+			} else {
+				text = "{+" + text + "+}"
+			}
+		}
+		parts = append(parts, text)
+	}
+	return strings.Join(parts, " ")
+}
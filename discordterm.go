@@ -1,15 +1,14 @@
 package discordterm
 
 import (
-	"bufio"
-	"errors"
+	"bytes"
 	"fmt"
 	"image"
 	"log"
-	"net/http"
 	"os"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/Necroforger/textify"
 	"github.com/bwmarrin/discordgo"
@@ -62,6 +61,16 @@ type Client struct {
 	// In a channel other than the currently active one
 	UnreadChannels map[string]map[string]int
 
+	// sentMessages is a map[channelid][]messageid of the messages
+	// Sent by the client's user, oldest first
+	sentMessages map[string][]string
+
+	// typing is a map[channelid][userid] of when users last started typing
+	typing map[string]map[string]time.Time
+	// The last time we sent a typing event, and to which channel
+	lastTyping        time.Time
+	lastTypingChannel string
+
 	Conf *Config
 }
 
@@ -76,8 +85,28 @@ type Config struct {
 	ImageWidth  uint
 	ImageHeight uint
 
+	// Play animated images in place instead of showing the first frame.
+	// Only messages printed by commands are played, not new messages
+	AutoPlay bool
+	// The maximum number of times to loop an animation. Zero loops forever
+	AnimationLoops int
+
 	// Show users' nicknames in the chat
 	ShowNicknames bool
+
+	// List channels the user can not view instead of hiding them
+	ShowHiddenChannels bool
+
+	// Print notices when messages in the active channel are edited or deleted
+	ShowEdits   bool
+	ShowDeletes bool
+
+	// Print notices when users join, leave or move between voice channels in the active guild
+	ShowVoiceActivity bool
+
+	// How to send messages longer than MaxMessageLength.
+	// One of OverflowAsk, OverflowSplit or OverflowUpload
+	OverflowMode string
 }
 
 // NewConfig returns the default config
@@ -87,6 +116,13 @@ func NewConfig() *Config {
 		ColorImages: true,
 		ShowImages:  true,
 		ImageWidth:  100,
+
+		AnimationLoops: 3,
+		OverflowMode:   OverflowAsk,
+		ShowEdits:      true,
+		ShowDeletes:    true,
+
+		ShowVoiceActivity: true,
 	}
 	return conf
 }
@@ -100,16 +136,19 @@ func NewClient(s *discordgo.Session, conf *Config) *Client {
 		Cli:            s,
 		Conf:           conf,
 		UnreadChannels: map[string]map[string]int{},
+		sentMessages:   map[string][]string{},
+		typing:         map[string]map[string]time.Time{},
+	}
+	// Cache recent messages so that edits and deletions can be displayed
+	if s.State != nil && s.State.MaxMessageCount == 0 {
+		s.State.MaxMessageCount = 100
 	}
 	c.addHandlers()
 	return c
 }
 
-// PrintImageComplex accepts a config struct
-func (c *Client) PrintImageComplex(img image.Image, conf *Config) error {
-	if conf == nil {
-		conf = NewConfig()
-	}
+// renderImage converts an image to text with the given config
+func (c *Client) renderImage(img image.Image, conf *Config) ([]byte, error) {
 	opts := textify.NewOptions()
 	opts.Width = conf.ImageWidth
 	opts.Height = conf.ImageHeight
@@ -120,13 +159,26 @@ func (c *Client) PrintImageComplex(img image.Image, conf *Config) error {
 		opts.ColorMode = textify.ColorTerminal
 	}
 
-	out := bufio.NewWriterSize(os.Stdout, 1024*1024*5)
-	err := textify.NewEncoder(out).Encode(img, opts)
+	var buf bytes.Buffer
+	err := textify.NewEncoder(&buf).Encode(img, opts)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// PrintImageComplex accepts a config struct
+func (c *Client) PrintImageComplex(img image.Image, conf *Config) error {
+	if conf == nil {
+		conf = NewConfig()
+	}
+
+	text, err := c.renderImage(img, conf)
 	if err != nil {
 		return err
 	}
-	out.Flush()
-	return nil
+	_, err = os.Stdout.Write(text)
+	return err
 }
 
 // PrintImage prints an image to the terminal screen with the client settings
@@ -136,13 +188,7 @@ func (c *Client) PrintImage(img image.Image) error {
 
 // PrintImageURLComplex accepts a config struct
 func (c *Client) PrintImageURLComplex(path string, conf *Config) error {
-	resp, err := http.Get(path)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	img, _, err := image.Decode(resp.Body)
+	img, err := FetchImage(path)
 	if err != nil {
 		return err
 	}
@@ -257,8 +303,16 @@ func (c *Client) PrintAttachments(attachments []*discordgo.MessageAttachment, co
 			} else {
 				fmt.Println(a.Filename, " \t", a.URL)
 			}
-			if conf.ShowImages {
-				c.PrintImageURLComplex(a.URL, conf)
+			if conf.ShowImages && IsImageAttachment(a) {
+				var err error
+				if conf.AutoPlay && IsAnimatedAttachment(a) {
+					err = c.PlayImageURLComplex(a.URL, conf)
+				} else {
+					err = c.PrintImageURLComplex(a.URL, conf)
+				}
+				if err != nil {
+					log.Println(err)
+				}
 			}
 		}
 	}
@@ -270,40 +324,13 @@ func (c *Client) PrintMessageComplex(m *discordgo.Message, conf *Config) {
 		conf = NewConfig()
 	}
 
-	var displayName string
-
-	// Fetch user nickname or use regular username
-	if !conf.ShowNicknames || func() error {
-		channel, err := c.Cli.State.Channel(m.ChannelID)
-		if err != nil {
-			channel, err = c.Cli.Channel(m.ChannelID)
-			if err != nil {
-				return err
-			}
-		}
-		member, err := c.Cli.State.Member(channel.GuildID, m.Author.ID)
-		if err != nil { /*
-				member, err = c.Cli.GuildMember(channel.GuildID, m.Author.ID)
-				if err != nil {
-					return err
-				}
-				// add member to state to prevent future API requests
-				err := c.Cli.State.MemberAdd(member)
-				if err != nil {
-					log.Println(err)
-					return err
-				}*/
-			return err
-		}
-		if member.Nick != "" {
-			displayName = member.Nick
-			return nil
-		}
-		return errors.New("User has no nickname")
-	}() != nil {
-		displayName = m.Author.Username
+	// Render events such as pins on a single line
+	if c.PrintSystemMessage(m, conf) {
+		return
 	}
 
+	displayName := c.DisplayName(m.ChannelID, m.Author, conf)
+
 	paddingUseridLeft := strings.Repeat(" ", maxInt(0, 30-len(displayName)))
 
 	if conf.ColorText {
@@ -322,6 +349,81 @@ func (c *Client) PrintMessageComplex(m *discordgo.Message, conf *Config) {
 	fmt.Println()
 }
 
+// DisplayName returns the name to display for a user in a channel.
+// This is the user's nickname in the channel's guild if ShowNicknames is enabled
+// And the user has one, otherwise their username.
+func (c *Client) DisplayName(channelID string, user *discordgo.User, conf *Config) string {
+	if user == nil {
+		return ""
+	}
+	if !conf.ShowNicknames {
+		return user.Username
+	}
+
+	channel, err := c.Cli.State.Channel(channelID)
+	if err != nil {
+		channel, err = c.Cli.Channel(channelID)
+		if err != nil {
+			return user.Username
+		}
+	}
+	member, err := c.Cli.State.Member(channel.GuildID, user.ID)
+	if err != nil || member.Nick == "" {
+		return user.Username
+	}
+	return member.Nick
+}
+
+// PrintMessageEdit prints a notice that a message was edited, with a word diff
+// Of the changes when the previous content is known
+func (c *Client) PrintMessageEdit(before, after *discordgo.Message, conf *Config) {
+	author := after.Author
+	if author == nil && before != nil {
+		author = before.Author
+	}
+	name := c.DisplayName(after.ChannelID, author, conf)
+	if name == "" {
+		name = "Someone"
+	}
+
+	var change string
+	if before != nil && before.Content != "" {
+		change = FormatWordDiff(WordDiff(before.Content, after.Content), conf.ColorText)
+	} else {
+		change = after.Content
+	}
+
+	if conf.ColorText {
+		fmt.Println(Brown("*"), Cyan(name), "edited", Blue(after.ID).String()+":", change)
+	} else {
+		fmt.Println("*", name, "edited", after.ID+":", change)
+	}
+}
+
+// PrintMessageDelete prints a notice that a message was deleted.
+// before is the deleted message if it was cached
+func (c *Client) PrintMessageDelete(messageID string, before *discordgo.Message, conf *Config) {
+	if before == nil || before.Author == nil {
+		if conf.ColorText {
+			fmt.Println(Brown("*"), "Message", Blue(messageID), "was deleted")
+		} else {
+			fmt.Println("*", "Message", messageID, "was deleted")
+		}
+		return
+	}
+
+	name := c.DisplayName(before.ChannelID, before.Author, conf)
+	content := strings.Replace(before.Content, "\n", " ", -1)
+	if len(content) > 80 {
+		content = content[:80] + "~"
+	}
+	if conf.ColorText {
+		fmt.Println(Brown("*"), Cyan(name), "deleted", Blue(messageID).String()+":", Gray(content))
+	} else {
+		fmt.Println("*", name, "deleted", messageID+":", content)
+	}
+}
+
 // PrintMessage prints a message to the console
 func (c *Client) PrintMessage(m *discordgo.Message) {
 	c.PrintMessageComplex(m, c.Conf)
@@ -329,7 +431,20 @@ func (c *Client) PrintMessage(m *discordgo.Message) {
 
 func (c *Client) addHandlers() {
 	c.Cli.AddHandler(func(_ *discordgo.Session, m *discordgo.MessageCreate) {
+		// Remember our own messages so they can be corrected or undone
+		if m.Author != nil && c.Cli.State.User != nil && m.Author.ID == c.Cli.State.User.ID {
+			c.TrackSentMessage(m.ChannelID, m.ID)
+		}
+		// Users stop typing once their message is sent
+		if m.Author != nil {
+			c.setTyping(m.ChannelID, m.Author.ID, false)
+		}
+
 		channel, err := c.Cli.State.Channel(m.ChannelID)
+		if err != nil && m.GuildID != "" {
+			// Messages in threads we have not seen yet
+			channel, err = c.Channel(m.ChannelID)
+		}
 		if err != nil {
 			log.Println(err)
 			return
@@ -339,12 +454,63 @@ func (c *Client) addHandlers() {
 			return
 		}
 		if m.ChannelID == c.ActiveChannel() {
-			c.PrintMessage(m.Message)
+			// Animations are not played while the prompt is active since the terminal
+			// Is in raw mode and Ctrl-C could not stop them. They can be played with /play
+			conf := *c.Conf
+			conf.AutoPlay = false
+			c.PrintMessageComplex(m.Message, &conf)
 		} else {
 			// Add 1 unread message to the unread message counter
 			c.MarkUnread(guild.ID, channel.ID, 1)
 		}
 	})
+
+	c.Cli.AddHandler(func(_ *discordgo.Session, v *discordgo.VoiceStateUpdate) {
+		if !c.Conf.ShowVoiceActivity || v.GuildID != c.ActiveGuild() {
+			return
+		}
+		c.PrintVoiceStateUpdate(v, c.Conf)
+	})
+
+	c.Cli.AddHandler(func(_ *discordgo.Session, t *discordgo.TypingStart) {
+		if c.Cli.State.User != nil && t.UserID == c.Cli.State.User.ID {
+			return
+		}
+		c.setTyping(t.ChannelID, t.UserID, true)
+	})
+
+	c.Cli.AddHandler(func(_ *discordgo.Session, m *discordgo.MessageUpdate) {
+		if !c.Conf.ShowEdits || m.ChannelID != c.ActiveChannel() {
+			return
+		}
+		// Updates without content are embeds being added to a message
+		if m.Content == "" || (m.BeforeUpdate != nil && m.BeforeUpdate.Content == m.Content) {
+			return
+		}
+		c.PrintMessageEdit(m.BeforeUpdate, m.Message, c.Conf)
+	})
+
+	c.Cli.AddHandler(func(_ *discordgo.Session, m *discordgo.MessageDelete) {
+		c.ForgetSentMessage(m.ChannelID, m.ID)
+		if !c.Conf.ShowDeletes || m.ChannelID != c.ActiveChannel() {
+			return
+		}
+		c.PrintMessageDelete(m.ID, m.BeforeDelete, c.Conf)
+	})
+
+	c.Cli.AddHandler(func(_ *discordgo.Session, m *discordgo.MessageDeleteBulk) {
+		for _, id := range m.Messages {
+			c.ForgetSentMessage(m.ChannelID, id)
+		}
+		if !c.Conf.ShowDeletes || m.ChannelID != c.ActiveChannel() {
+			return
+		}
+		if c.Conf.ColorText {
+			fmt.Println(Brown("*"), len(m.Messages), "messages were deleted")
+		} else {
+			fmt.Println("*", len(m.Messages), "messages were deleted")
+		}
+	})
 }
 
 // MarkUnread marks a channel as unread
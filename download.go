@@ -0,0 +1,283 @@
+package discordterm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ProgressBar prints the progress of a download on a single line
+type ProgressBar struct {
+	sync.Mutex
+	Name  string
+	Total int64
+	Width int
+
+	// Count shows the progress as a count of items instead of bytes
+	Count bool
+
+	done int64
+	out  io.Writer
+}
+
+// NewProgressBar returns a progress bar that writes to out
+func NewProgressBar(out io.Writer, name string, total int64) *ProgressBar {
+	return &ProgressBar{
+		Name:  name,
+		Total: total,
+		Width: 30,
+		out:   out,
+	}
+}
+
+// Write counts the bytes written and redraws the bar
+func (p *ProgressBar) Write(b []byte) (int, error) {
+	p.Add(int64(len(b)))
+	return len(b), nil
+}
+
+// Add adds n to the progress and redraws the bar
+func (p *ProgressBar) Add(n int64) {
+	p.Lock()
+	defer p.Unlock()
+	p.done += n
+	p.draw()
+}
+
+// SetName changes the name shown after the bar and redraws it
+func (p *ProgressBar) SetName(name string) {
+	p.Lock()
+	defer p.Unlock()
+	if p.Name == name {
+		return
+	}
+	// Clear the rest of the line in case the name got shorter
+	fmt.Fprint(p.out, "\r\x1b[K")
+	p.Name = name
+	p.draw()
+}
+
+// Draw redraws the progress bar
+func (p *ProgressBar) Draw() {
+	p.Lock()
+	defer p.Unlock()
+	p.draw()
+}
+
+func (p *ProgressBar) draw() {
+	format := FormatBytes
+	if p.Count {
+		format = func(n int64) string { return strconv.FormatInt(n, 10) }
+	}
+	if p.Total <= 0 {
+		fmt.Fprintf(p.out, "\r%s %s", format(p.done), p.Name)
+		return
+	}
+
+	filled := int(int64(p.Width) * p.done / p.Total)
+	filled = minInt(filled, p.Width)
+	bar := strings.Repeat("=", filled)
+	if filled < p.Width {
+		bar += ">" + strings.Repeat(" ", p.Width-filled-1)
+	}
+	fmt.Fprintf(p.out, "\r[%s] %3d%% %s/%s %s", bar, 100*p.done/p.Total, format(p.done), format(p.Total), p.Name)
+}
+
+// Finish ends the progress bar's line
+func (p *ProgressBar) Finish() {
+	fmt.Fprintln(p.out)
+}
+
+// FormatBytes formats a number of bytes in a human readable form
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return strconv.FormatInt(n, 10) + "B"
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// SanitizeFilename replaces characters that are not safe to use in a filename
+func SanitizeFilename(name string) string {
+	name = filepath.Base(strings.Replace(name, "\\", "/", -1))
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		if r < 32 {
+			return '_'
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == ".." {
+		name = "file"
+	}
+	return name
+}
+
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// DownloadFile downloads a URL into dir under the given filename.
+// If a file with the same name and contents already exists, it is reused and
+// existed is true. If a different file has the same name, the file is saved
+// Under a name containing its checksum so that repeated downloads of the
+// same file always map to the same path.
+// Progress is written to progress if it is not nil.
+func DownloadFile(url, dir, filename string, progress io.Writer) (path string, existed bool, err error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", false, err
+	}
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", false, fmt.Errorf("could not download %s: %s", url, resp.Status)
+	}
+
+	tmp, err := ioutil.TempFile(dir, ".download-")
+	if err != nil {
+		return "", false, err
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	w := io.MultiWriter(tmp, h)
+	if progress != nil {
+		w = io.MultiWriter(w, progress)
+	}
+	_, err = io.Copy(w, resp.Body)
+	tmp.Close()
+	if err != nil {
+		return "", false, err
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+
+	filename = SanitizeFilename(filename)
+	ext := filepath.Ext(filename)
+	base := strings.TrimSuffix(filename, ext)
+	candidates := []string{
+		filename,
+		base + "-" + sum[:12] + ext,
+	}
+	for i := 1; ; i++ {
+		var name string
+		if i <= len(candidates) {
+			name = candidates[i-1]
+		} else {
+			name = base + "-" + sum[:12] + "-" + strconv.Itoa(i-len(candidates)) + ext
+		}
+		path = filepath.Join(dir, name)
+
+		existing, err := fileChecksum(path)
+		if os.IsNotExist(err) {
+			return path, false, os.Rename(tmp.Name(), path)
+		}
+		if err != nil {
+			return "", false, err
+		}
+		if existing == sum {
+			return path, true, nil
+		}
+	}
+}
+
+// savedIndexName is the file in a download directory that records which
+// Attachments were saved into it
+const savedIndexName = ".saved-attachments"
+
+// SavedAttachments returns the attachments that SaveAttachment saved into dir
+// Whose files still exist, mapping attachment IDs to paths
+func SavedAttachments(dir string) map[string]string {
+	saved := make(map[string]string)
+	data, err := ioutil.ReadFile(filepath.Join(dir, savedIndexName))
+	if err != nil {
+		return saved
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		parts := strings.SplitN(line, "\t", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		path := filepath.Join(dir, parts[1])
+		if _, err := os.Stat(path); err == nil {
+			saved[parts[0]] = path
+		}
+	}
+	return saved
+}
+
+// recordSavedAttachment adds an attachment to the index of dir
+func recordSavedAttachment(dir, id, path string) error {
+	f, err := os.OpenFile(filepath.Join(dir, savedIndexName), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = fmt.Fprintf(f, "%s\t%s\n", id, filepath.Base(path))
+	return err
+}
+
+// SaveAttachment downloads a message attachment into dir, drawing a progress bar
+// To standard output. Saved attachments are recorded in dir so that
+// SavedAttachments can find them without downloading them again.
+func (c *Client) SaveAttachment(a *discordgo.MessageAttachment, dir string) (path string, existed bool, err error) {
+	bar := NewProgressBar(os.Stdout, a.Filename, int64(a.Size))
+	path, existed, err = DownloadFile(a.URL, dir, a.Filename, bar)
+	bar.Finish()
+	if err != nil {
+		return "", false, err
+	}
+	if err := recordSavedAttachment(dir, a.ID, path); err != nil {
+		log.Println(err)
+	}
+	return path, existed, nil
+}
+
+// AttachmentType returns a broad type for an attachment based on its extension.
+// One of image, video, audio, text or other.
+func AttachmentType(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".png", ".apng", ".jpg", ".jpeg", ".gif", ".webp", ".bmp", ".svg":
+		return "image"
+	case ".mp4", ".webm", ".mov", ".mkv", ".avi":
+		return "video"
+	case ".mp3", ".ogg", ".wav", ".flac", ".m4a", ".opus":
+		return "audio"
+	case ".txt", ".md", ".log", ".json", ".csv", ".go", ".py", ".js", ".c", ".h", ".cpp", ".rs", ".java":
+		return "text"
+	default:
+		return "other"
+	}
+}
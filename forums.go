@@ -0,0 +1,90 @@
+package discordterm
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// FormatForumTag returns a tag's name, with its emoji if it has a unicode emoji
+func FormatForumTag(t *discordgo.ForumTag) string {
+	if t.EmojiName != "" && t.EmojiID == "" {
+		return t.EmojiName + " " + t.Name
+	}
+	return t.Name
+}
+
+// Forum is a forum channel and the tags its posts can use
+type Forum struct {
+	*discordgo.Channel
+}
+
+// IsForumType returns true for channel types whose messages are posts
+func IsForumType(t discordgo.ChannelType) bool {
+	return t == discordgo.ChannelTypeGuildForum || t == discordgo.ChannelTypeGuildMedia
+}
+
+// Tag finds a tag of the forum by name or ID, without regard to case
+func (f *Forum) Tag(name string) *discordgo.ForumTag {
+	for i := range f.AvailableTags {
+		if t := &f.AvailableTags[i]; t.ID == name || strings.EqualFold(t.Name, name) {
+			return t
+		}
+	}
+	return nil
+}
+
+// TagNames returns the names of the tags with the given IDs
+func (f *Forum) TagNames(ids []string) []string {
+	names := []string{}
+	for _, id := range ids {
+		if t := f.Tag(id); t != nil {
+			names = append(names, FormatForumTag(t))
+		}
+	}
+	return names
+}
+
+// Forum fetches a forum channel with its tags
+func (c *Client) Forum(channelID string) (*Forum, error) {
+	ch, err := c.Cli.Channel(channelID)
+	if err != nil {
+		return nil, err
+	}
+	if !IsForumType(ch.Type) {
+		return nil, errors.New("channel " + channelID + " is not a forum")
+	}
+	return &Forum{ch}, nil
+}
+
+// ForumPosts returns the active and archived posts of a forum,
+// Most recently active first
+func (c *Client) ForumPosts(forumID string) ([]*Thread, error) {
+	return c.ChannelThreads(forumID)
+}
+
+// CreateForumPost creates a post in a forum with the given title, tags and message
+func (c *Client) CreateForumPost(forumID, title string, tagIDs []string, content string) (*Thread, error) {
+	if strings.TrimSpace(title) == "" {
+		return nil, errors.New("a post needs a title")
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil, errors.New("a post needs a message")
+	}
+	if utf8.RuneCountInString(content) > MaxMessageLength {
+		return nil, errors.New("the message of a post can not be longer than 2000 characters")
+	}
+
+	ch, err := c.Cli.ForumThreadStartComplex(forumID, &discordgo.ThreadStart{
+		Name:                title,
+		AutoArchiveDuration: DefaultAutoArchiveDuration,
+		AppliedTags:         tagIDs,
+	}, &discordgo.MessageSend{Content: content})
+	if err != nil {
+		return nil, err
+	}
+	c.addThreads([]*discordgo.Channel{ch})
+	return &Thread{ch}, nil
+}
@@ -0,0 +1,204 @@
+package discordterm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+
+	// Register the image formats discord serves
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/bwmarrin/discordgo"
+	_ "golang.org/x/image/webp"
+)
+
+// Limits applied to images before they are decoded
+const (
+	// MaxImageBytes is the largest image file that will be downloaded
+	MaxImageBytes = 50 * 1024 * 1024
+
+	// MaxImageDimension is the largest width or height an image can have
+	MaxImageDimension = 16384
+
+	// MaxImagePixels is the largest number of pixels an image can have
+	MaxImagePixels = 40 * 1000 * 1000
+)
+
+// Image errors
+var (
+	ErrUnsupportedImage = errors.New("unsupported image format")
+	ErrImageTooLarge    = errors.New("image is too large to display")
+)
+
+// Magic numbers of the supported image formats
+var imageSignatures = []struct {
+	format string
+	offset int
+	magic  []byte
+}{
+	{"png", 0, []byte("\x89PNG\r\n\x1a\n")},
+	{"jpeg", 0, []byte("\xff\xd8\xff")},
+	{"gif", 0, []byte("GIF87a")},
+	{"gif", 0, []byte("GIF89a")},
+	{"webp", 8, []byte("WEBP")},
+}
+
+// Content types of the supported image formats
+var imageContentTypes = map[string]string{
+	"image/png":  "png",
+	"image/apng": "png",
+	"image/jpeg": "jpeg",
+	"image/jpg":  "jpeg",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// SniffImageFormat returns the format of an image from the first bytes of its data.
+// contentType is only used to produce a more useful error when the data is not
+// a supported image.
+func SniffImageFormat(contentType string, header []byte) (string, error) {
+	for _, sig := range imageSignatures {
+		if len(header) < sig.offset+len(sig.magic) {
+			continue
+		}
+		if !bytes.Equal(header[sig.offset:sig.offset+len(sig.magic)], sig.magic) {
+			continue
+		}
+		if sig.format == "webp" && !bytes.HasPrefix(header, []byte("RIFF")) {
+			continue
+		}
+		return sig.format, nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "" {
+		return "", ErrUnsupportedImage
+	}
+	if _, ok := imageContentTypes[mediaType]; ok {
+		return "", fmt.Errorf("%v: data does not look like %s", ErrUnsupportedImage, mediaType)
+	}
+	return "", fmt.Errorf("%v: %s", ErrUnsupportedImage, mediaType)
+}
+
+// IsImageContentType reports whether a content type is one of the supported image formats
+func IsImageContentType(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	_, ok := imageContentTypes[mediaType]
+	return ok
+}
+
+// IsImageFilename reports whether a filename has the extension of a supported image format
+func IsImageFilename(name string) bool {
+	name = strings.ToLower(name)
+	for _, ext := range []string{".png", ".apng", ".jpg", ".jpeg", ".gif", ".webp"} {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsImageAttachment reports whether an attachment is an image in a supported format,
+// By its filename or its content type since image links often have no extension
+func IsImageAttachment(a *discordgo.MessageAttachment) bool {
+	return IsImageFilename(a.Filename) || IsImageContentType(a.ContentType)
+}
+
+// ReadImageData reads image data from r, rejecting files larger than MaxImageBytes
+func ReadImageData(r io.Reader) ([]byte, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, MaxImageBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxImageBytes {
+		return nil, fmt.Errorf("%v: file is larger than %d bytes", ErrImageTooLarge, MaxImageBytes)
+	}
+	return data, nil
+}
+
+// CheckImageConfig validates the dimensions of an image before it is decoded
+// To guard against decompression bombs
+func CheckImageConfig(cfg image.Config) error {
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return fmt.Errorf("invalid image dimensions %dx%d", cfg.Width, cfg.Height)
+	}
+	if cfg.Width > MaxImageDimension || cfg.Height > MaxImageDimension ||
+		cfg.Width*cfg.Height > MaxImagePixels {
+		return fmt.Errorf("%v: %dx%d", ErrImageTooLarge, cfg.Width, cfg.Height)
+	}
+	return nil
+}
+
+// DecodeImageData sniffs, validates and decodes image data
+func DecodeImageData(data []byte, contentType string) (image.Image, string, error) {
+	format, err := SniffImageFormat(contentType, data)
+	if err != nil {
+		return nil, "", err
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, format, fmt.Errorf("could not read %s header: %v", format, err)
+	}
+	if err := CheckImageConfig(cfg); err != nil {
+		return nil, format, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, format, fmt.Errorf("could not decode %s image: %v", format, err)
+	}
+	return img, format, nil
+}
+
+// DecodeImage reads and decodes an image from r
+func DecodeImage(r io.Reader, contentType string) (image.Image, string, error) {
+	data, err := ReadImageData(r)
+	if err != nil {
+		return nil, "", err
+	}
+	return DecodeImageData(data, contentType)
+}
+
+// FetchImageData downloads image data from a URL, returning the data and its content type
+func FetchImageData(path string) ([]byte, string, error) {
+	resp, err := http.Get(path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("could not fetch image %s: %s", path, resp.Status)
+	}
+	if resp.ContentLength > MaxImageBytes {
+		return nil, "", fmt.Errorf("%v: %s is %d bytes", ErrImageTooLarge, path, resp.ContentLength)
+	}
+
+	data, err := ReadImageData(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, resp.Header.Get("Content-Type"), nil
+}
+
+// FetchImage downloads and decodes an image from a URL
+func FetchImage(path string) (image.Image, error) {
+	data, contentType, err := FetchImageData(path)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := DecodeImageData(data, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("could not display image %s: %v", path, err)
+	}
+	return img, nil
+}
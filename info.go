@@ -0,0 +1,222 @@
+package discordterm
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	. "github.com/logrusorgru/aurora"
+)
+
+// printField prints a label and value on a line of an info view
+func printField(conf *Config, label string, value interface{}) {
+	padding := strings.Repeat(" ", maxInt(0, 16-len(label)))
+	if conf.ColorText {
+		fmt.Println(Cyan(label+":"), padding, value)
+	} else {
+		fmt.Println(label+":", padding, value)
+	}
+}
+
+// snowflakeDate formats the creation date of a discord ID
+func snowflakeDate(id string) string {
+	t, err := discordgo.SnowflakeTimestamp(id)
+	if err != nil {
+		return "unknown"
+	}
+	return t.Local().Format("2006-01-02 15:04:05") + " (" + FormatAge(time.Since(t)) + " ago)"
+}
+
+// FormatAge formats a duration in days, or hours or minutes for short durations
+func FormatAge(d time.Duration) string {
+	if d < time.Hour {
+		return fmt.Sprintf("%d minutes", int(d.Minutes()))
+	}
+	if d < 48*time.Hour {
+		return fmt.Sprintf("%d hours", int(d.Hours()))
+	}
+	return fmt.Sprintf("%d days", int(d.Hours()/24))
+}
+
+// guildRole returns the role with the given ID in a guild
+func guildRole(guild *discordgo.Guild, roleID string) *discordgo.Role {
+	for _, r := range guild.Roles {
+		if r.ID == roleID {
+			return r
+		}
+	}
+	return nil
+}
+
+// PrintGuildInfo prints information about a guild
+func (c *Client) PrintGuildInfo(guildID string, conf *Config) error {
+	guild, err := c.Cli.State.Guild(guildID)
+	if err != nil {
+		guild, err = c.Cli.Guild(guildID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if guild.Icon != "" {
+		err := c.PrintImageURLComplex(guild.IconURL(""), conf)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+
+	owner := guild.OwnerID
+	if member, err := c.Cli.State.Member(guild.ID, guild.OwnerID); err == nil {
+		// Users that moved to unique usernames have no discriminator
+		owner = member.User.String() + " (" + guild.OwnerID + ")"
+	}
+
+	online := 0
+	for _, p := range guild.Presences {
+		if p.Status != discordgo.StatusOffline && p.Status != discordgo.StatusInvisible {
+			online++
+		}
+	}
+
+	counts := map[string]int{}
+	for _, ch := range guild.Channels {
+		counts[ChannelTypeName(ch.Type)]++
+	}
+	channelCounts := []string{}
+	for _, t := range []discordgo.ChannelType{
+		discordgo.ChannelTypeGuildCategory,
+		discordgo.ChannelTypeGuildText,
+		discordgo.ChannelTypeGuildVoice,
+		discordgo.ChannelTypeGuildNews,
+		discordgo.ChannelTypeGuildStageVoice,
+		discordgo.ChannelTypeGuildForum,
+	} {
+		if n := counts[ChannelTypeName(t)]; n > 0 {
+			channelCounts = append(channelCounts, fmt.Sprintf("%d %s", n, ChannelTypeName(t)))
+		}
+	}
+
+	if conf.ColorText {
+		fmt.Println(Red(guild.Name))
+	} else {
+		fmt.Println(guild.Name)
+	}
+	if guild.Description != "" {
+		fmt.Println(guild.Description)
+	}
+	printField(conf, "ID", guild.ID)
+	printField(conf, "Owner", owner)
+	printField(conf, "Created", snowflakeDate(guild.ID))
+	printField(conf, "Members", fmt.Sprintf("%d (%d online)", guild.MemberCount, online))
+	printField(conf, "Boost tier", fmt.Sprintf("%d (%d boosts)", guild.PremiumTier, guild.PremiumSubscriptionCount))
+	printField(conf, "Channels", fmt.Sprintf("%d: %s", len(guild.Channels), strings.Join(channelCounts, ", ")))
+	printField(conf, "Emojis", len(guild.Emojis))
+	printField(conf, "Roles", len(guild.Roles))
+
+	roles := make([]*discordgo.Role, len(guild.Roles))
+	copy(roles, guild.Roles)
+	sort.Sort(discordgo.Roles(roles))
+	for _, role := range roles {
+		if conf.ColorText {
+			fmt.Println("    ", Cyan(role.ID), "\t", Green(role.Name))
+		} else {
+			fmt.Println("    ", role.ID, "\t", role.Name)
+		}
+	}
+	return nil
+}
+
+// PrintChannelInfo prints information about a channel
+func (c *Client) PrintChannelInfo(channelID string, conf *Config) error {
+	channel, err := c.Channel(channelID)
+	if err != nil {
+		return err
+	}
+	guild, _ := c.Cli.State.Guild(channel.GuildID)
+
+	if conf.ColorText {
+		fmt.Println(Red("#" + channel.Name))
+	} else {
+		fmt.Println("#" + channel.Name)
+	}
+	if channel.Topic != "" {
+		fmt.Println(channel.Topic)
+	}
+	printField(conf, "ID", channel.ID)
+	printField(conf, "Type", ChannelTypeName(channel.Type))
+	printField(conf, "Created", snowflakeDate(channel.ID))
+	if channel.ParentID != "" {
+		parentName := channel.ParentID
+		if parent, err := c.Cli.State.Channel(channel.ParentID); err == nil {
+			parentName = parent.Name
+		}
+		if IsThreadType(channel.Type) {
+			printField(conf, "Channel", "#"+parentName)
+		} else {
+			printField(conf, "Category", parentName)
+		}
+	}
+	if t, ok := c.Thread(channel.ID); ok {
+		printField(conf, "Messages", t.MessageCount)
+		printField(conf, "Members", t.MemberCount)
+		printField(conf, "Archived", t.Archived())
+	}
+	printField(conf, "Position", channel.Position)
+	if channel.RateLimitPerUser > 0 {
+		printField(conf, "Slowmode", (time.Duration(channel.RateLimitPerUser) * time.Second).String())
+	} else {
+		printField(conf, "Slowmode", "off")
+	}
+	printField(conf, "NSFW", channel.NSFW)
+	if channel.Type == discordgo.ChannelTypeGuildVoice {
+		printField(conf, "Bitrate", channel.Bitrate)
+		printField(conf, "User limit", channel.UserLimit)
+	}
+
+	if len(channel.PermissionOverwrites) == 0 {
+		return nil
+	}
+	fmt.Println("Permission overwrites:")
+	for _, o := range channel.PermissionOverwrites {
+		fmt.Println("    ", c.overwriteTargetName(guild, o))
+		if o.Allow != 0 {
+			allow := strings.Join(PermissionNames(int64(o.Allow)), ", ")
+			if conf.ColorText {
+				fmt.Println("        ", Green("allow:"), allow)
+			} else {
+				fmt.Println("        ", "allow:", allow)
+			}
+		}
+		if o.Deny != 0 {
+			deny := strings.Join(PermissionNames(int64(o.Deny)), ", ")
+			if conf.ColorText {
+				fmt.Println("        ", Red("deny: "), deny)
+			} else {
+				fmt.Println("        ", "deny: ", deny)
+			}
+		}
+	}
+	return nil
+}
+
+// overwriteTargetName returns a readable name for the role or member
+// A permission overwrite applies to
+func (c *Client) overwriteTargetName(guild *discordgo.Guild, o *discordgo.PermissionOverwrite) string {
+	if o.Type == discordgo.PermissionOverwriteTypeRole {
+		if guild != nil {
+			if role := guildRole(guild, o.ID); role != nil {
+				return "role " + role.Name + " (" + o.ID + ")"
+			}
+		}
+		return "role " + o.ID
+	}
+	if guild != nil {
+		if member, err := c.Cli.State.Member(guild.ID, o.ID); err == nil {
+			return "member " + member.User.Username + " (" + o.ID + ")"
+		}
+	}
+	return "member " + o.ID
+}
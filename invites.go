@@ -0,0 +1,92 @@
+package discordterm
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Limits of invites
+const (
+	MaxInviteAge  = 7 * 24 * time.Hour
+	MaxInviteUses = 100
+)
+
+// inviteURLPrefixes are the prefixes of invite links
+var inviteURLPrefixes = []string{
+	"discord.gg/",
+	"discord.com/invite/",
+	"discordapp.com/invite/",
+}
+
+// InviteURL returns the link of an invite code
+func InviteURL(code string) string {
+	return "https://discord.gg/" + code
+}
+
+// ParseInviteCode returns the code of an invite link, or code itself
+// If it is not a link
+func ParseInviteCode(code string) string {
+	code = strings.TrimSpace(code)
+	code = strings.TrimPrefix(code, "https://")
+	code = strings.TrimPrefix(code, "http://")
+	code = strings.TrimPrefix(code, "www.")
+	for _, prefix := range inviteURLPrefixes {
+		code = strings.TrimPrefix(code, prefix)
+	}
+	if i := strings.IndexAny(code, "/?#"); i != -1 {
+		code = code[:i]
+	}
+	return code
+}
+
+// InviteExpiry returns when an invite expires, and false if it never expires
+func InviteExpiry(inv *discordgo.Invite) (time.Time, bool) {
+	if inv.MaxAge == 0 {
+		return time.Time{}, false
+	}
+	return inv.CreatedAt.Add(time.Duration(inv.MaxAge) * time.Second), true
+}
+
+// CreateInvite creates a unique invite to a channel. A maxAge or maxUses of 0 means no limit.
+// Temporary invites give membership that ends when the user disconnects
+// Unless they are given a role.
+func (c *Client) CreateInvite(channelID string, maxAge time.Duration, maxUses int, temporary bool) (*discordgo.Invite, error) {
+	if maxAge < 0 || maxAge > MaxInviteAge {
+		return nil, errors.New("invites can last at most 7 days, or forever with a max age of 0")
+	}
+	if maxUses < 0 || maxUses > MaxInviteUses {
+		return nil, errors.New("invites can have at most 100 uses, or unlimited uses with 0")
+	}
+	return c.Cli.ChannelInviteCreate(channelID, discordgo.Invite{
+		MaxAge:    int(maxAge.Seconds()),
+		MaxUses:   maxUses,
+		Temporary: temporary,
+		Unique:    true,
+	})
+}
+
+// GuildInvites returns the invites of a guild, most used first
+func (c *Client) GuildInvites(guildID string) ([]*discordgo.Invite, error) {
+	invites, err := c.Cli.GuildInvites(guildID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(invites, func(i, j int) bool {
+		return invites[i].Uses > invites[j].Uses
+	})
+	return invites, nil
+}
+
+// RevokeInvite deletes an invite by code or link
+func (c *Client) RevokeInvite(code string) (*discordgo.Invite, error) {
+	return c.Cli.InviteDelete(ParseInviteCode(code))
+}
+
+// InviteInfo returns an invite by code or link, with the approximate member counts of its guild
+func (c *Client) InviteInfo(code string) (*discordgo.Invite, error) {
+	return c.Cli.InviteWithCounts(ParseInviteCode(code))
+}
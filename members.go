@@ -0,0 +1,278 @@
+package discordterm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// membersPageSize is the largest number of members discord returns at once
+const membersPageSize = 1000
+
+// ResolveMember finds a guild member by ID, mention, username, username#discriminator
+// Or nickname. Names are matched without regard to case.
+func (c *Client) ResolveMember(guildID, query string) (*discordgo.Member, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, errors.New("no member given")
+	}
+
+	// Mentions look like <@id> or <@!id>
+	if strings.HasPrefix(query, "<@") && strings.HasSuffix(query, ">") {
+		query = strings.TrimPrefix(query[2:len(query)-1], "!")
+	}
+	if IsSnowflake(query) {
+		return c.Member(guildID, query)
+	}
+
+	guild, err := c.Cli.State.Guild(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Cli.State.RLock()
+	defer c.Cli.State.RUnlock()
+
+	q := strings.ToLower(strings.TrimPrefix(query, "@"))
+	var matches []*discordgo.Member
+	for _, m := range guild.Members {
+		if m.User == nil {
+			continue
+		}
+		tag := strings.ToLower(m.User.Username + "#" + m.User.Discriminator)
+		if tag == q {
+			return m, nil
+		}
+		if strings.ToLower(m.User.Username) == q || strings.ToLower(m.Nick) == q {
+			matches = append(matches, m)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, errors.New("no member named " + query + " was found")
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, errors.New("more than one member is named " + query + ", use their ID instead")
+	}
+}
+
+// IsSnowflake returns true if id looks like a complete discord ID
+func IsSnowflake(id string) bool {
+	if len(id) < 15 {
+		return false
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// ResolveChannel finds a channel in a guild by ID, mention or name
+func (c *Client) ResolveChannel(guildID, query string) (*discordgo.Channel, error) {
+	query = strings.TrimSpace(query)
+	if strings.HasPrefix(query, "<#") && strings.HasSuffix(query, ">") {
+		query = query[2 : len(query)-1]
+	}
+	if IsSnowflake(query) {
+		return c.Channel(query)
+	}
+
+	channels, err := c.GuildChannels(guildID)
+	if err != nil {
+		return nil, err
+	}
+	q := strings.ToLower(strings.TrimPrefix(query, "#"))
+	for _, ch := range channels {
+		if strings.ToLower(ch.Name) == q {
+			return ch, nil
+		}
+	}
+	return nil, errors.New("no channel named " + query + " was found")
+}
+
+// AllMembers fetches every member of a guild, a page at a time.
+// progress is called with the number of members fetched so far after each page.
+func (c *Client) AllMembers(guildID string, progress func(n int)) ([]*discordgo.Member, error) {
+	members := []*discordgo.Member{}
+	after := ""
+	for {
+		page, err := c.Cli.GuildMembers(guildID, after, membersPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range page {
+			m.GuildID = guildID
+			c.Cli.State.MemberAdd(m)
+		}
+		members = append(members, page...)
+		if progress != nil {
+			progress(len(members))
+		}
+		if len(page) < membersPageSize {
+			return members, nil
+		}
+		after = page[len(page)-1].User.ID
+	}
+}
+
+// MemberFilter selects members
+type MemberFilter func(m *discordgo.Member) bool
+
+// ParseMemberFilter parses a comma separated list of conditions that
+// Members must all match. A condition is negated by prefixing it with !
+//
+//	all            every member
+//	bot, human     bots or people
+//	role:NAME      members with a role, by name or ID
+//	noroles        members without any roles
+//	name:REGEX     members whose username or nickname matches
+//	joined<7d      members that joined less than 7 days ago
+//	joined>12h     members that joined more than 12 hours ago
+//	booster        members boosting the guild
+func (c *Client) ParseMemberFilter(guildID, expr string) (MemberFilter, error) {
+	filters := []MemberFilter{}
+	for _, cond := range strings.Split(expr, ",") {
+		cond = strings.TrimSpace(cond)
+		if cond == "" {
+			continue
+		}
+		negate := strings.HasPrefix(cond, "!")
+		f, err := c.parseMemberCondition(guildID, strings.TrimPrefix(cond, "!"))
+		if err != nil {
+			return nil, err
+		}
+		if negate {
+			inner := f
+			f = func(m *discordgo.Member) bool { return !inner(m) }
+		}
+		filters = append(filters, f)
+	}
+	if len(filters) == 0 {
+		return nil, errors.New("the filter is empty, use \"all\" to select every member")
+	}
+
+	return func(m *discordgo.Member) bool {
+		for _, f := range filters {
+			if !f(m) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// parseMemberCondition parses a single condition of a member filter
+func (c *Client) parseMemberCondition(guildID, cond string) (MemberFilter, error) {
+	lower := strings.ToLower(cond)
+	switch {
+	case lower == "all":
+		return func(*discordgo.Member) bool { return true }, nil
+	case lower == "bot", lower == "human":
+		bot := lower == "bot"
+		return func(m *discordgo.Member) bool { return m.User != nil && m.User.Bot == bot }, nil
+	case lower == "noroles":
+		return func(m *discordgo.Member) bool { return len(m.Roles) == 0 }, nil
+	case lower == "booster":
+		return func(m *discordgo.Member) bool { return m.PremiumSince != nil }, nil
+
+	case strings.HasPrefix(lower, "role:"):
+		role, err := c.ResolveRole(guildID, cond[len("role:"):])
+		if err != nil {
+			return nil, err
+		}
+		return func(m *discordgo.Member) bool { return HasRole(m, role.ID) }, nil
+
+	case strings.HasPrefix(lower, "name:"):
+		re, err := regexp.Compile("(?i)" + cond[len("name:"):])
+		if err != nil {
+			return nil, err
+		}
+		return func(m *discordgo.Member) bool {
+			return (m.User != nil && re.MatchString(m.User.Username)) || (m.Nick != "" && re.MatchString(m.Nick))
+		}, nil
+
+	case strings.HasPrefix(lower, "joined<"), strings.HasPrefix(lower, "joined>"):
+		d, err := ParseDays(cond[len("joined<"):])
+		if err != nil {
+			return nil, err
+		}
+		newer := lower[len("joined")] == '<'
+		return func(m *discordgo.Member) bool {
+			return (time.Since(m.JoinedAt) < d) == newer
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown filter condition: %s", cond)
+}
+
+// ParseDays parses a duration that may also be given in days or weeks, such as 7d or 2w
+func ParseDays(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	unit := time.Duration(0)
+	switch {
+	case strings.HasSuffix(s, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(s, "w"):
+		unit = 7 * 24 * time.Hour
+	default:
+		return time.ParseDuration(s)
+	}
+	n, err := strconv.ParseFloat(s[:len(s)-1], 64)
+	if err != nil {
+		return 0, errors.New("invalid duration: " + s)
+	}
+	return time.Duration(n * float64(unit)), nil
+}
+
+// HasRole returns true if a member has a role
+func HasRole(m *discordgo.Member, roleID string) bool {
+	for _, id := range m.Roles {
+		if id == roleID {
+			return true
+		}
+	}
+	return false
+}
+
+// MemberFailure is a member that a change could not be applied to
+type MemberFailure struct {
+	Member *discordgo.Member
+	Err    error
+}
+
+// ChangeMembersRole adds a role to or removes a role from each member, drawing
+// Progress to out. Requests wait out rate limits, which are shown on the progress bar.
+// The members that could not be changed are returned.
+func (c *Client) ChangeMembersRole(guildID, roleID string, members []*discordgo.Member, add bool, out io.Writer) []MemberFailure {
+	bar := NewProgressBar(out, "members", int64(len(members)))
+	bar.Count = true
+	bar.Draw()
+
+	defer c.rateLimitProgress(bar)()
+
+	failures := []MemberFailure{}
+	for _, m := range members {
+		var err error
+		if add {
+			err = c.Cli.GuildMemberRoleAdd(guildID, m.User.ID, roleID)
+		} else {
+			err = c.Cli.GuildMemberRoleRemove(guildID, m.User.ID, roleID)
+		}
+		if err != nil {
+			failures = append(failures, MemberFailure{m, err})
+		}
+		bar.SetName("members")
+		bar.Add(1)
+	}
+	bar.Finish()
+	return failures
+}
@@ -0,0 +1,101 @@
+package discordterm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestParseDays(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		err  bool
+	}{
+		{"7d", 7 * 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"1.5d", 36 * time.Hour, false},
+		{"12h", 12 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"d", 0, true},
+		{"7", 0, true},
+		{"xd", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDays(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("ParseDays(%q) error = %v, want error %v", tt.in, err, tt.err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDays(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseMemberFilter(t *testing.T) {
+	s, err := discordgo.New("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewClient(s, nil)
+	s.State.GuildAdd(&discordgo.Guild{
+		ID:    "1",
+		Roles: []*discordgo.Role{{ID: "10", Name: "Mods"}},
+	})
+
+	now := time.Now()
+	boosted := now.Add(-time.Hour)
+	members := map[string]*discordgo.Member{
+		"new":     {User: &discordgo.User{Username: "new"}, JoinedAt: now.Add(-24 * time.Hour)},
+		"old":     {User: &discordgo.User{Username: "old"}, JoinedAt: now.Add(-30 * 24 * time.Hour), Roles: []string{"10"}},
+		"bot":     {User: &discordgo.User{Username: "helper", Bot: true}, JoinedAt: now.Add(-30 * 24 * time.Hour)},
+		"booster": {User: &discordgo.User{Username: "shiny"}, Nick: "Booster", JoinedAt: now.Add(-10 * 24 * time.Hour), PremiumSince: &boosted},
+	}
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"all", []string{"new", "old", "bot", "booster"}},
+		{"bot", []string{"bot"}},
+		{"human", []string{"new", "old", "booster"}},
+		{"!bot", []string{"new", "old", "booster"}},
+		{"noroles", []string{"new", "bot", "booster"}},
+		{"!noroles", []string{"old"}},
+		{"role:mods", []string{"old"}},
+		{"role:10", []string{"old"}},
+		{"!role:<@&10>", []string{"new", "bot", "booster"}},
+		{"booster", []string{"booster"}},
+		{"name:^boo", []string{"booster"}},
+		{"name:help", []string{"bot"}},
+		{"joined<7d", []string{"new"}},
+		{"joined>7d", []string{"old", "bot", "booster"}},
+		{"!joined<7d", []string{"old", "bot", "booster"}},
+		{"joined>7d, joined<2w", []string{"booster"}},
+		{"human,joined>7d,!booster", []string{"old"}},
+	}
+	for _, tt := range tests {
+		f, err := c.ParseMemberFilter("1", tt.expr)
+		if err != nil {
+			t.Errorf("ParseMemberFilter(%q) error = %v", tt.expr, err)
+			continue
+		}
+		want := map[string]bool{}
+		for _, name := range tt.want {
+			want[name] = true
+		}
+		for name, m := range members {
+			if got := f(m); got != want[name] {
+				t.Errorf("ParseMemberFilter(%q) matches %s = %v, want %v", tt.expr, name, got, want[name])
+			}
+		}
+	}
+
+	for _, expr := range []string{"", " , ", "joined<soon", "role:admins", "name:(", "flying"} {
+		if _, err := c.ParseMemberFilter("1", expr); err == nil {
+			t.Errorf("ParseMemberFilter(%q) error = nil, want an error", expr)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package discordterm
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Limits of the moderation endpoints
+const (
+	MaxTimeout        = 28 * 24 * time.Hour
+	MaxBanDeleteDays  = 7
+	maxBansPageSize   = 1000
+	auditLogLookupMax = 20
+)
+
+// ResolveUser finds a user by ID, mention or the name of a member of the guild.
+// Users that are not members can only be found by ID or mention.
+func (c *Client) ResolveUser(guildID, query string) (*discordgo.User, error) {
+	id := strings.TrimSpace(query)
+	if strings.HasPrefix(id, "<@") && strings.HasSuffix(id, ">") {
+		id = strings.TrimPrefix(id[2:len(id)-1], "!")
+	}
+	if !IsSnowflake(id) {
+		m, err := c.ResolveMember(guildID, query)
+		if err != nil {
+			return nil, err
+		}
+		return m.User, nil
+	}
+	if m, err := c.Member(guildID, id); err == nil {
+		return m.User, nil
+	}
+	return c.Cli.User(id)
+}
+
+// auditLogReason returns the request option that records a reason in the guild's audit log,
+// Or no options if the reason is empty. The reason is escaped since it is sent in a header.
+func auditLogReason(reason string) []discordgo.RequestOption {
+	if reason == "" {
+		return nil
+	}
+	return []discordgo.RequestOption{discordgo.WithAuditLogReason(url.PathEscape(reason))}
+}
+
+// Kick removes a member from a guild
+func (c *Client) Kick(guildID, userID, reason string) error {
+	return c.Cli.GuildMemberDelete(guildID, userID, auditLogReason(reason)...)
+}
+
+// Ban bans a user from a guild, deleting their messages from the last deleteDays days
+func (c *Client) Ban(guildID, userID, reason string, deleteDays int) error {
+	if deleteDays < 0 || deleteDays > MaxBanDeleteDays {
+		return errors.New("messages can be deleted from at most the last " + strconv.Itoa(MaxBanDeleteDays) + " days")
+	}
+	// The reason is sent in the header, not the deprecated query parameter
+	return c.Cli.GuildBanCreateWithReason(guildID, userID, "", deleteDays, auditLogReason(reason)...)
+}
+
+// Unban removes the ban of a user
+func (c *Client) Unban(guildID, userID, reason string) error {
+	return c.Cli.GuildBanDelete(guildID, userID, auditLogReason(reason)...)
+}
+
+// Timeout stops a member from talking in a guild for a duration and returns when
+// The timeout ends, as reported by discord. A duration of zero removes the member's timeout.
+func (c *Client) Timeout(guildID, userID string, d time.Duration, reason string) (time.Time, error) {
+	if d < 0 || d > MaxTimeout {
+		return time.Time{}, errors.New("a timeout can be at most 28 days long")
+	}
+	// A zero time is sent as null, which removes the timeout
+	var until time.Time
+	if d > 0 {
+		until = time.Now().Add(d).UTC()
+	}
+	m, err := c.Cli.GuildMemberEdit(guildID, userID, &discordgo.GuildMemberParams{CommunicationDisabledUntil: &until}, auditLogReason(reason)...)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	timedOut := m.CommunicationDisabledUntil != nil && m.CommunicationDisabledUntil.After(time.Now())
+	switch {
+	case d > 0 && !timedOut:
+		return time.Time{}, errors.New("discord did not apply the timeout")
+	case d == 0 && timedOut:
+		return *m.CommunicationDisabledUntil, errors.New("discord did not remove the timeout")
+	case d == 0:
+		return time.Time{}, nil
+	}
+	return *m.CommunicationDisabledUntil, nil
+}
+
+// Bans returns a page of a guild's bans, ordered by user ID, starting after the given user ID
+func (c *Client) Bans(guildID, after string, limit int) ([]*discordgo.GuildBan, error) {
+	return c.Cli.GuildBans(guildID, limit, "", after)
+}
+
+// FindBan finds a ban by user ID, mention or username
+func (c *Client) FindBan(guildID, query string) (*discordgo.GuildBan, error) {
+	id := strings.TrimSpace(query)
+	if strings.HasPrefix(id, "<@") && strings.HasSuffix(id, ">") {
+		id = strings.TrimPrefix(id[2:len(id)-1], "!")
+	}
+	if IsSnowflake(id) {
+		return c.Cli.GuildBan(guildID, id)
+	}
+
+	name := strings.ToLower(strings.TrimPrefix(id, "@"))
+	after := ""
+	for {
+		bans, err := c.Bans(guildID, after, maxBansPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range bans {
+			if strings.ToLower(b.User.Username) == name || strings.ToLower(b.User.Username+"#"+b.User.Discriminator) == name {
+				return b, nil
+			}
+		}
+		if len(bans) < maxBansPageSize {
+			return nil, errors.New("no banned user named " + query + " was found")
+		}
+		after = bans[len(bans)-1].User.ID
+	}
+}
+
+// RecordedReason returns the reason recorded in the audit log for the
+// Client's user's latest action of the given type on a target
+func (c *Client) RecordedReason(guildID string, action discordgo.AuditLogAction, targetID string) (string, error) {
+	log, err := c.Cli.GuildAuditLog(guildID, c.Cli.State.User.ID, "", int(action), auditLogLookupMax)
+	if err != nil {
+		return "", err
+	}
+	for _, e := range log.AuditLogEntries {
+		if e.TargetID == targetID {
+			return e.Reason, nil
+		}
+	}
+	return "", errors.New("the action was not found in the audit log")
+}
@@ -0,0 +1,264 @@
+package discordterm
+
+import (
+	"sort"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Permission is a named permission bit
+type Permission struct {
+	Name string
+	Bit  int64
+}
+
+// Permissions lists every known permission by name, in bit order.
+// Names follow the discord API documentation in lower case.
+var Permissions = []Permission{
+	{"create_instant_invite", 1 << 0},
+	{"kick_members", 1 << 1},
+	{"ban_members", 1 << 2},
+	{"administrator", 1 << 3},
+	{"manage_channels", 1 << 4},
+	{"manage_guild", 1 << 5},
+	{"add_reactions", 1 << 6},
+	{"view_audit_log", 1 << 7},
+	{"priority_speaker", 1 << 8},
+	{"stream", 1 << 9},
+	{"view_channel", 1 << 10},
+	{"send_messages", 1 << 11},
+	{"send_tts_messages", 1 << 12},
+	{"manage_messages", 1 << 13},
+	{"embed_links", 1 << 14},
+	{"attach_files", 1 << 15},
+	{"read_message_history", 1 << 16},
+	{"mention_everyone", 1 << 17},
+	{"use_external_emojis", 1 << 18},
+	{"view_guild_insights", 1 << 19},
+	{"connect", 1 << 20},
+	{"speak", 1 << 21},
+	{"mute_members", 1 << 22},
+	{"deafen_members", 1 << 23},
+	{"move_members", 1 << 24},
+	{"use_vad", 1 << 25},
+	{"change_nickname", 1 << 26},
+	{"manage_nicknames", 1 << 27},
+	{"manage_roles", 1 << 28},
+	{"manage_webhooks", 1 << 29},
+	{"manage_emojis", 1 << 30},
+	{"use_application_commands", 1 << 31},
+	{"request_to_speak", 1 << 32},
+	{"manage_events", 1 << 33},
+	{"manage_threads", 1 << 34},
+	{"create_public_threads", 1 << 35},
+	{"create_private_threads", 1 << 36},
+	{"use_external_stickers", 1 << 37},
+	{"send_messages_in_threads", 1 << 38},
+	{"use_embedded_activities", 1 << 39},
+	{"moderate_members", 1 << 40},
+}
+
+// PermissionNames returns the names of the permissions set in bits
+func PermissionNames(bits int64) []string {
+	names := []string{}
+	for _, p := range Permissions {
+		if bits&p.Bit != 0 {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+// Permission bits used when computing effective permissions
+const (
+	PermissionViewChannel   int64 = 1 << 10
+	PermissionAdministrator int64 = 1 << 3
+	PermissionAllBits       int64 = 1<<41 - 1
+)
+
+// PermissionState is the effective state of a permission for a member,
+// And the role or overwrite that decided it
+type PermissionState struct {
+	Permission
+	Allowed bool
+	Reason  string
+}
+
+// PermissionBreakdown is the effective permissions of a member,
+// Optionally in a channel
+type PermissionBreakdown struct {
+	Permissions int64
+	States      []PermissionState
+}
+
+// Has returns true if every permission in bits is allowed
+func (p *PermissionBreakdown) Has(bits int64) bool {
+	return p.Permissions&bits == bits
+}
+
+// Member returns a guild member from the state, falling back
+// To the API and caching the result
+func (c *Client) Member(guildID, userID string) (*discordgo.Member, error) {
+	if m, err := c.Cli.State.Member(guildID, userID); err == nil {
+		return m, nil
+	}
+	m, err := c.Cli.GuildMember(guildID, userID)
+	if err != nil {
+		return nil, err
+	}
+	m.GuildID = guildID
+	c.Cli.State.MemberAdd(m)
+	return m, nil
+}
+
+// ComputePermissions computes the effective permissions of a member from the
+// Guild's roles and, if channelID is not empty, the channel's permission overwrites.
+// Each permission is annotated with the role or overwrite that granted or denied it.
+func (c *Client) ComputePermissions(guildID, userID, channelID string) (*PermissionBreakdown, error) {
+	guild, err := c.Cli.State.Guild(guildID)
+	if err != nil {
+		return nil, err
+	}
+	member, err := c.Member(guildID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var channel *discordgo.Channel
+	if channelID != "" {
+		channel, err = c.Cli.State.Channel(channelID)
+		if err != nil {
+			return nil, err
+		}
+		// Threads use the permission overwrites of their channel
+		if IsThreadType(channel.Type) {
+			channel, err = c.Cli.State.Channel(channel.ParentID)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var perms int64
+	reasons := map[int64]string{}
+	set := func(bits int64, allow bool, reason string) {
+		for _, p := range Permissions {
+			if bits&p.Bit == 0 {
+				continue
+			}
+			if allow {
+				perms |= p.Bit
+			} else {
+				perms &^= p.Bit
+			}
+			reasons[p.Bit] = reason
+		}
+	}
+	result := func() *PermissionBreakdown {
+		b := &PermissionBreakdown{Permissions: perms}
+		for _, p := range Permissions {
+			reason, ok := reasons[p.Bit]
+			if !ok {
+				reason = "not granted by any role"
+			}
+			b.States = append(b.States, PermissionState{p, perms&p.Bit != 0, reason})
+		}
+		return b
+	}
+
+	if guild.OwnerID == userID {
+		set(PermissionAllBits, true, "server owner")
+		return result(), nil
+	}
+
+	// Base permissions from @everyone and the member's roles.
+	// The @everyone role has the same ID as the guild
+	if everyone := guildRole(guild, guild.ID); everyone != nil {
+		set(int64(everyone.Permissions), true, "role @everyone")
+	}
+	roles := []*discordgo.Role{}
+	for _, id := range member.Roles {
+		if role := guildRole(guild, id); role != nil {
+			roles = append(roles, role)
+		}
+	}
+	sort.Sort(discordgo.Roles(roles))
+	// Apply the lowest roles first so that the highest granting role is the reason
+	for i := len(roles) - 1; i >= 0; i-- {
+		set(int64(roles[i].Permissions), true, "role "+roles[i].Name)
+	}
+
+	if perms&PermissionAdministrator != 0 {
+		set(PermissionAllBits, true, "administrator ("+reasons[PermissionAdministrator]+")")
+		return result(), nil
+	}
+	if channel == nil {
+		return result(), nil
+	}
+
+	// Channel overwrites are applied in order: @everyone, roles, then the member
+	var (
+		everyoneOverwrite *discordgo.PermissionOverwrite
+		memberOverwrite   *discordgo.PermissionOverwrite
+		roleOverwrites    []*discordgo.PermissionOverwrite
+	)
+	for _, o := range channel.PermissionOverwrites {
+		switch {
+		case o.ID == guild.ID:
+			everyoneOverwrite = o
+		case o.Type == discordgo.PermissionOverwriteTypeMember:
+			if o.ID == userID {
+				memberOverwrite = o
+			}
+		default:
+			for _, id := range member.Roles {
+				if o.ID == id {
+					roleOverwrites = append(roleOverwrites, o)
+				}
+			}
+		}
+	}
+
+	where := " in #" + channel.Name
+	if everyoneOverwrite != nil {
+		set(int64(everyoneOverwrite.Deny), false, "denied by overwrite for @everyone"+where)
+		set(int64(everyoneOverwrite.Allow), true, "allowed by overwrite for @everyone"+where)
+	}
+	for _, o := range roleOverwrites {
+		name := o.ID
+		if role := guildRole(guild, o.ID); role != nil {
+			name = role.Name
+		}
+		set(int64(o.Deny), false, "denied by overwrite for role "+name+where)
+	}
+	for _, o := range roleOverwrites {
+		name := o.ID
+		if role := guildRole(guild, o.ID); role != nil {
+			name = role.Name
+		}
+		set(int64(o.Allow), true, "allowed by overwrite for role "+name+where)
+	}
+	if memberOverwrite != nil {
+		set(int64(memberOverwrite.Deny), false, "denied by overwrite for member"+where)
+		set(int64(memberOverwrite.Allow), true, "allowed by overwrite for member"+where)
+	}
+
+	// Members that can not view a channel can not do anything in it
+	if perms&PermissionViewChannel == 0 {
+		set(perms, false, "view_channel is denied"+where)
+	}
+	return result(), nil
+}
+
+// CanViewChannel returns true if the client's user can view a channel
+func (c *Client) CanViewChannel(channel *discordgo.Channel) bool {
+	if channel.GuildID == "" || c.Cli.State.User == nil {
+		return true
+	}
+	perms, err := c.ComputePermissions(channel.GuildID, c.Cli.State.User.ID, channel.ID)
+	if err != nil {
+		// Assume the channel is visible when permissions can not be computed
+		return true
+	}
+	return perms.Has(PermissionViewChannel)
+}
@@ -0,0 +1,142 @@
+package discordterm
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Limits of bulk message deletion
+const (
+	// BulkDeleteMaxAge is the age after which messages can no longer be bulk deleted.
+	// A minute is left as a margin for the time it takes to delete them.
+	BulkDeleteMaxAge = 14*24*time.Hour - time.Minute
+	// BulkDeleteMax is the most messages that can be bulk deleted at once
+	BulkDeleteMax = 100
+	// PurgeScanLimit is the most messages that are searched for matches
+	PurgeScanLimit = 5000
+)
+
+// MessageFilter selects messages. Empty fields match every message.
+type MessageFilter struct {
+	UserID   string
+	Contains string
+	Regex    *regexp.Regexp
+	Bots     bool
+}
+
+// Match returns true if a message matches every condition of the filter
+func (f *MessageFilter) Match(m *discordgo.Message) bool {
+	if f.UserID != "" && (m.Author == nil || m.Author.ID != f.UserID) {
+		return false
+	}
+	if f.Bots && (m.Author == nil || !m.Author.Bot) {
+		return false
+	}
+	if f.Contains != "" && !strings.Contains(strings.ToLower(m.Content), strings.ToLower(f.Contains)) {
+		return false
+	}
+	if f.Regex != nil && !f.Regex.MatchString(m.Content) {
+		return false
+	}
+	return true
+}
+
+// SelectMessages searches a channel's history, newest first, for up to n messages
+// Matching a filter. If before is not empty the search starts before that message.
+// At most PurgeScanLimit messages are searched.
+func (c *Client) SelectMessages(channelID string, n int, before string, filter *MessageFilter) ([]*discordgo.Message, error) {
+	selected := []*discordgo.Message{}
+	scanned := 0
+	for len(selected) < n && scanned < PurgeScanLimit {
+		page, err := c.Cli.ChannelMessages(channelID, 100, before, "", "")
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range page {
+			if filter.Match(m) {
+				selected = append(selected, m)
+				if len(selected) == n {
+					break
+				}
+			}
+		}
+		scanned += len(page)
+		if len(page) < 100 {
+			break
+		}
+		before = page[len(page)-1].ID
+	}
+	return selected, nil
+}
+
+// canBulkDelete returns true if a message is young enough to be bulk deleted
+func canBulkDelete(m *discordgo.Message) bool {
+	t, err := discordgo.SnowflakeTimestamp(m.ID)
+	return err == nil && time.Since(t) < BulkDeleteMaxAge
+}
+
+// rateLimitProgress shows rate limits on a progress bar until the returned function is called
+func (c *Client) rateLimitProgress(bar *ProgressBar) (remove func()) {
+	name := bar.Name
+	return c.Cli.AddHandler(func(_ *discordgo.Session, r *discordgo.RateLimit) {
+		bar.SetName(fmt.Sprintf("%s (rate limited, waiting %s)", name, r.RetryAfter))
+	})
+}
+
+// PurgeMessages deletes messages from a channel, drawing progress to out.
+// Messages younger than two weeks are bulk deleted, older messages are deleted
+// One at a time. The errors of the deletions that failed are returned.
+func (c *Client) PurgeMessages(channelID string, messages []*discordgo.Message, out io.Writer) (deleted int, failures []error) {
+	young := []string{}
+	old := []string{}
+	for _, m := range messages {
+		if canBulkDelete(m) {
+			young = append(young, m.ID)
+		} else {
+			old = append(old, m.ID)
+		}
+	}
+
+	bar := NewProgressBar(out, "messages", int64(len(messages)))
+	bar.Count = true
+	bar.Draw()
+	defer c.rateLimitProgress(bar)()
+
+	for len(young) > 0 {
+		n := minInt(len(young), BulkDeleteMax)
+		chunk := young[:n]
+		young = young[n:]
+
+		var err error
+		if len(chunk) == 1 {
+			// Bulk deletes need at least two messages
+			err = c.Cli.ChannelMessageDelete(channelID, chunk[0])
+		} else {
+			err = c.Cli.ChannelMessagesBulkDelete(channelID, chunk)
+		}
+		if err != nil {
+			failures = append(failures, err)
+		} else {
+			deleted += len(chunk)
+		}
+		bar.SetName("messages")
+		bar.Add(int64(len(chunk)))
+	}
+
+	for _, id := range old {
+		if err := c.Cli.ChannelMessageDelete(channelID, id); err != nil {
+			failures = append(failures, fmt.Errorf("%s: %v", id, err))
+		} else {
+			deleted++
+		}
+		bar.SetName("messages")
+		bar.Add(1)
+	}
+	bar.Finish()
+	return deleted, failures
+}
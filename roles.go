@@ -0,0 +1,236 @@
+package discordterm
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ParsePermission returns the bit of a permission by name. Names are not case
+// Sensitive and may use spaces or dashes in place of underscores.
+func ParsePermission(name string) (int64, error) {
+	n := strings.ToLower(strings.TrimSpace(name))
+	n = strings.NewReplacer("-", "_", " ", "_").Replace(n)
+	for _, p := range Permissions {
+		if p.Name == n {
+			return p.Bit, nil
+		}
+	}
+	return 0, errors.New("unknown permission: " + name)
+}
+
+// ParsePermissions parses a comma separated list of permission names,
+// Or a permission bitfield given as a number
+func ParsePermissions(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "none" {
+		return 0, nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, nil
+	}
+	var bits int64
+	for _, name := range strings.Split(s, ",") {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		bit, err := ParsePermission(name)
+		if err != nil {
+			return 0, err
+		}
+		bits |= bit
+	}
+	return bits, nil
+}
+
+// ApplyPermissions changes a permission bitfield with a list of permissions.
+// If every name in spec starts with + or - the permissions are added to
+// Or removed from current, otherwise spec replaces current.
+func ApplyPermissions(current int64, spec string) (int64, error) {
+	names := strings.Split(spec, ",")
+	relative := true
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if !strings.HasPrefix(name, "+") && !strings.HasPrefix(name, "-") {
+			relative = false
+		}
+	}
+	if !relative {
+		return ParsePermissions(spec)
+	}
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		bit, err := ParsePermission(name[1:])
+		if err != nil {
+			return 0, err
+		}
+		if name[0] == '+' {
+			current |= bit
+		} else {
+			current &^= bit
+		}
+	}
+	return current, nil
+}
+
+// ParseColor parses a color written as hex, such as #ff8800 or 0xff8800,
+// Or as a decimal number
+func ParseColor(s string) (int, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "none" || s == "default" {
+		return 0, nil
+	}
+	base := 10
+	switch {
+	case strings.HasPrefix(s, "#"):
+		s, base = s[1:], 16
+	case strings.HasPrefix(s, "0x"):
+		s, base = s[2:], 16
+	case len(s) == 6 && strings.IndexAny(s, "abcdef") != -1:
+		base = 16
+	}
+	n, err := strconv.ParseInt(s, base, 32)
+	if err != nil || n < 0 || n > 0xffffff {
+		return 0, errors.New("invalid color: " + s)
+	}
+	return int(n), nil
+}
+
+// ResolveRole finds a role of a guild by ID, mention or name.
+// Names are matched without regard to case.
+func (c *Client) ResolveRole(guildID, query string) (*discordgo.Role, error) {
+	query = strings.TrimSpace(query)
+	if strings.HasPrefix(query, "<@&") && strings.HasSuffix(query, ">") {
+		query = query[3 : len(query)-1]
+	}
+	roles, err := c.GuildRoles(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.ToLower(strings.TrimPrefix(query, "@"))
+	var matches []*discordgo.Role
+	for _, r := range roles {
+		if r.ID == query {
+			return r, nil
+		}
+		if strings.ToLower(strings.TrimPrefix(r.Name, "@")) == name {
+			matches = append(matches, r)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, errors.New("no role named " + query + " was found")
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, errors.New("more than one role is named " + query + ", use its ID instead")
+	}
+}
+
+// GuildRoles returns the roles of a guild from the state when possible,
+// Highest first
+func (c *Client) GuildRoles(guildID string) ([]*discordgo.Role, error) {
+	var roles []*discordgo.Role
+	if guild, err := c.Cli.State.Guild(guildID); err == nil && len(guild.Roles) > 0 {
+		c.Cli.State.RLock()
+		roles = make([]*discordgo.Role, len(guild.Roles))
+		copy(roles, guild.Roles)
+		c.Cli.State.RUnlock()
+	} else {
+		roles, err = c.Cli.GuildRoles(guildID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Sort(discordgo.Roles(roles))
+	return roles, nil
+}
+
+// CreateRole creates a role in a guild
+func (c *Client) CreateRole(guildID string, params *discordgo.RoleParams) (*discordgo.Role, error) {
+	role, err := c.Cli.GuildRoleCreate(guildID, params)
+	if err != nil {
+		return nil, err
+	}
+	c.Cli.State.RoleAdd(guildID, role)
+	return role, nil
+}
+
+// EditRole changes the fields of a role that are set in params
+func (c *Client) EditRole(guildID, roleID string, params *discordgo.RoleParams) (*discordgo.Role, error) {
+	role, err := c.Cli.GuildRoleEdit(guildID, roleID, params)
+	if err != nil {
+		return nil, err
+	}
+	c.Cli.State.RoleAdd(guildID, role)
+	return role, nil
+}
+
+// DeleteRole deletes a role from a guild
+func (c *Client) DeleteRole(guildID, roleID string) error {
+	if err := c.Cli.GuildRoleDelete(guildID, roleID); err != nil {
+		return err
+	}
+	c.Cli.State.RoleRemove(guildID, roleID)
+	return nil
+}
+
+// MoveRole moves a role to a position in the role list, where 1 is just above @everyone.
+// The positions of the roles in between are shifted to make room.
+func (c *Client) MoveRole(guildID, roleID string, position int) error {
+	roles, err := c.GuildRoles(guildID)
+	if err != nil {
+		return err
+	}
+
+	// Order the roles from lowest to highest without @everyone,
+	// Which always has position 0
+	ordered := []*discordgo.Role{}
+	var moved *discordgo.Role
+	for i := len(roles) - 1; i >= 0; i-- {
+		r := roles[i]
+		switch r.ID {
+		case guildID:
+		case roleID:
+			moved = r
+		default:
+			ordered = append(ordered, r)
+		}
+	}
+	if moved == nil {
+		return errors.New("role " + roleID + " was not found")
+	}
+	if position < 1 {
+		position = 1
+	}
+	if position > len(ordered)+1 {
+		position = len(ordered) + 1
+	}
+	ordered = append(ordered[:position-1], append([]*discordgo.Role{moved}, ordered[position-1:]...)...)
+
+	// Only the IDs and positions of the roles are read
+	positions := []*discordgo.Role{}
+	for i, r := range ordered {
+		if r.Position != i+1 {
+			positions = append(positions, &discordgo.Role{ID: r.ID, Position: i + 1})
+		}
+	}
+	if len(positions) == 0 {
+		return nil
+	}
+
+	updated, err := c.Cli.GuildRoleReorder(guildID, positions)
+	if err != nil {
+		return err
+	}
+	for _, r := range updated {
+		c.Cli.State.RoleAdd(guildID, r)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package discordterm
+
+import "testing"
+
+func TestParsePermissions(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+		err  bool
+	}{
+		{"", 0, false},
+		{"none", 0, false},
+		{"8", 8, false},
+		{"kick_members", 1 << 1, false},
+		{"Kick Members, ban-members", 1<<1 | 1<<2, false},
+		{"kick_members,", 1 << 1, false},
+		{"kick_members,fly", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePermissions(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("ParsePermissions(%q) error = %v, want error %v", tt.in, err, tt.err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParsePermissions(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestApplyPermissions(t *testing.T) {
+	const current = 1<<1 | 1<<2
+	tests := []struct {
+		spec string
+		want int64
+		err  bool
+	}{
+		{"+administrator", current | 1<<3, false},
+		{"-kick_members", 1 << 2, false},
+		{"+administrator,-kick_members", 1<<2 | 1<<3, false},
+		{" +administrator , -ban_members ", 1<<1 | 1<<3, false},
+		{"-administrator", current, false},
+		{"administrator", 1 << 3, false},
+		// A list that mixes relative and plain names replaces the permissions,
+		// So the signs are not valid names
+		{"+administrator,kick_members", 0, true},
+		{"+fly", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ApplyPermissions(current, tt.spec)
+		if (err != nil) != tt.err {
+			t.Errorf("ApplyPermissions(%q) error = %v, want error %v", tt.spec, err, tt.err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ApplyPermissions(%q) = %d, want %d", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+		err  bool
+	}{
+		{"#ff8800", 0xff8800, false},
+		{"0xFF8800", 0xff8800, false},
+		{"ff8800", 0xff8800, false},
+		// Six digits without a hex letter are read as decimal
+		{"123456", 123456, false},
+		{"#123456", 0x123456, false},
+		{"16777215", 0xffffff, false},
+		{"16777216", 0, true},
+		{"none", 0, false},
+		{"default", 0, false},
+		{"-1", 0, true},
+		{"#ggg", 0, true},
+		{"red", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseColor(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("ParseColor(%q) error = %v, want error %v", tt.in, err, tt.err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseColor(%q) = %#x, want %#x", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package discordterm
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// MaxMessageLength is the maximum number of characters in a message
+const MaxMessageLength = 2000
+
+// Ways of handling messages that are longer than MaxMessageLength
+const (
+	OverflowAsk    = "ask"
+	OverflowSplit  = "split"
+	OverflowUpload = "upload"
+)
+
+// OverflowFilename is the name of the file long messages are uploaded as
+const OverflowFilename = "message.txt"
+
+const codeFence = "```"
+
+type splitLine struct {
+	text string
+	// The opening fence of the code block this line leaves open, if any
+	fence string
+}
+
+// SplitMessage splits content into parts no longer than max characters.
+// Parts are split at paragraph boundaries when possible, then at line
+// Boundaries. Code blocks that span several parts are closed at the end of
+// One part and reopened with the same language at the start of the next.
+func SplitMessage(content string, max int) []string {
+	if utf8.RuneCountInString(content) <= max {
+		return []string{content}
+	}
+
+	lines := []splitLine{}
+	fence := ""
+	for _, text := range strings.Split(content, "\n") {
+		// A line that opens and closes a code block leaves it balanced
+		if t := strings.TrimSpace(text); strings.HasPrefix(t, codeFence) && strings.Count(t, codeFence)%2 == 1 {
+			if fence == "" {
+				fence = fenceLanguage(t)
+			} else {
+				fence = ""
+			}
+		}
+		lines = append(lines, splitLine{text, fence})
+	}
+
+	render := func(prefix string, part []splitLine) string {
+		texts := []string{}
+		if prefix != "" {
+			texts = append(texts, prefix)
+		}
+		for _, l := range part {
+			texts = append(texts, l.text)
+		}
+		if len(part) > 0 && part[len(part)-1].fence != "" {
+			texts = append(texts, codeFence)
+		}
+		return strings.Join(texts, "\n")
+	}
+	fits := func(prefix string, part []splitLine) bool {
+		return utf8.RuneCountInString(render(prefix, part)) <= max
+	}
+
+	parts := []string{}
+	prefix := ""
+	for len(lines) > 0 {
+		end := 0
+		for end < len(lines) && fits(prefix, lines[:end+1]) {
+			end++
+		}
+
+		// A line too long to fit in a part of its own is split within the line,
+		// Filling the rest of this part
+		if end < len(lines) && (end == 0 || !fits(lines[end-1].fence, lines[end:end+1])) {
+			line := lines[end]
+			room := max - utf8.RuneCountInString(render(prefix, append(lines[:end:end], splitLine{"", line.fence})))
+			switch {
+			case room >= 1:
+				head, tail := splitLineAt(line.text, room)
+				parts = append(parts, render(prefix, append(lines[:end:end], splitLine{head, line.fence})))
+				lines[end].text = tail
+				prefix = line.fence
+			case end == 0:
+				// The fences leave no room for the line, so it is split without them
+				head, tail := splitLineAt(line.text, max)
+				parts = append(parts, head)
+				lines[0].text = tail
+				prefix = ""
+			}
+			if room >= 1 || end == 0 {
+				lines = lines[end:]
+				if lines[0].text == "" {
+					lines = lines[1:]
+				}
+				continue
+			}
+		}
+
+		// Prefer to end the part on a paragraph boundary outside of a code block
+		// As long as it does not make the part too short
+		if end < len(lines) {
+			for b := end - 1; b > end/2; b-- {
+				if strings.TrimSpace(lines[b].text) == "" && lines[b].fence == "" {
+					end = b + 1
+					break
+				}
+			}
+		}
+
+		if part := strings.TrimSpace(render(prefix, lines[:end])); part != "" {
+			parts = append(parts, part)
+		}
+		prefix = lines[end-1].fence
+		lines = lines[end:]
+	}
+	return parts
+}
+
+// fenceLanguage returns the opening fence of a code block with its language,
+// Leaving out the rest of the line
+func fenceLanguage(line string) string {
+	info := strings.TrimPrefix(line, codeFence)
+	if i := strings.IndexAny(info, " `"); i != -1 {
+		info = info[:i]
+	}
+	return codeFence + info
+}
+
+// splitLineAt splits text after at most n characters, on a space if possible
+func splitLineAt(text string, n int) (string, string) {
+	if n < 1 {
+		n = 1
+	}
+	i, count := 0, 0
+	for i < len(text) && count < n {
+		_, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		count++
+	}
+	if i >= len(text) {
+		return text, ""
+	}
+	if sp := strings.LastIndex(text[:i], " "); sp > 0 {
+		return text[:sp], text[sp+1:]
+	}
+	return text[:i], text[i:]
+}
+
+// SendMessageParts sends each part as a separate message.
+// The first part is sent as a reply to ref if it is not nil
+func (c *Client) SendMessageParts(channelID string, parts []string, ref *discordgo.MessageReference) error {
+	for i, part := range parts {
+		data := &discordgo.MessageSend{Content: part}
+		if i == 0 {
+			data.Reference = ref
+		}
+		_, err := c.Cli.ChannelMessageSendComplex(channelID, data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendMessageFile sends content as a text file attachment
+// In reply to ref if it is not nil
+func (c *Client) SendMessageFile(channelID, content string, ref *discordgo.MessageReference) (*discordgo.Message, error) {
+	return c.Cli.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		File: &discordgo.File{
+			Name:        OverflowFilename,
+			ContentType: "text/plain",
+			Reader:      strings.NewReader(content),
+		},
+		Reference: ref,
+	})
+}
+
+// SendLongMessageComplex sends a message, splitting it into several messages
+// Or uploading it as a file, depending on mode, when its content is too long.
+func (c *Client) SendLongMessageComplex(channelID string, data *discordgo.MessageSend, mode string) error {
+	if utf8.RuneCountInString(data.Content) <= MaxMessageLength {
+		_, err := c.Cli.ChannelMessageSendComplex(channelID, data)
+		return err
+	}
+
+	switch mode {
+	case OverflowSplit:
+		return c.SendMessageParts(channelID, SplitMessage(data.Content, MaxMessageLength), data.Reference)
+	case OverflowUpload:
+		_, err := c.SendMessageFile(channelID, data.Content, data.Reference)
+		return err
+	default:
+		return errors.New("message is longer than 2000 characters")
+	}
+}
+
+// SendLongMessage sends a message, splitting it into several messages
+// Or uploading it as a file, depending on mode, when it is too long.
+func (c *Client) SendLongMessage(channelID, content, mode string) error {
+	return c.SendLongMessageComplex(channelID, &discordgo.MessageSend{Content: content}, mode)
+}
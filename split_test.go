@@ -0,0 +1,92 @@
+package discordterm
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// checkParts fails the test if a part is longer than max, or is empty or leaves
+// A code block open when balanced is true
+func checkParts(t *testing.T, name string, parts []string, max int, balanced bool) {
+	t.Helper()
+	for i, part := range parts {
+		if n := utf8.RuneCountInString(part); n > max {
+			t.Errorf("%s: part %d has %d characters, want at most %d", name, i, n, max)
+		}
+		if !balanced {
+			continue
+		}
+		if strings.TrimSpace(strings.Replace(part, codeFence, "", -1)) == "" {
+			t.Errorf("%s: part %d is empty: %q", name, i, part)
+		}
+		fences := 0
+		for _, line := range strings.Split(part, "\n") {
+			if t := strings.TrimSpace(line); strings.HasPrefix(t, codeFence) {
+				fences += strings.Count(t, codeFence)
+			}
+		}
+		if fences%2 != 0 {
+			t.Errorf("%s: part %d leaves a code block open:\n%s", name, i, part)
+		}
+	}
+}
+
+func TestSplitMessage(t *testing.T) {
+	prose := strings.Repeat("word ", 600)
+	lines := strings.Repeat("a line of text\n", 200)
+	code := "```go\n" + strings.Repeat("fmt.Println(\"hello\")\n", 150) + "```"
+
+	tests := []struct {
+		name    string
+		content string
+		max     int
+		parts   int
+	}{
+		{"short", "hello", 100, 1},
+		{"prose", prose, 2000, 2},
+		{"paragraphs", strings.Repeat("a paragraph of text\n\n", 200), 2000, 3},
+		{"code block", "before\n" + code + "\nafter", 2000, 2},
+		{"one line fence", "```make test```\n" + lines, 2000, 2},
+		{"long fence line", "```go " + strings.Repeat("x", 150) + "\ncode\n```", 100, 3},
+		{"long line in code block", "```\n" + strings.Repeat("y", 300) + "\n```", 100, 4},
+		// The fences do not fit, so the parts can not be balanced
+		{"small max", code, 8, 0},
+	}
+	for _, tt := range tests {
+		parts := SplitMessage(tt.content, tt.max)
+		if tt.parts != 0 && len(parts) != tt.parts {
+			t.Errorf("%s: got %d parts, want %d", tt.name, len(parts), tt.parts)
+		}
+		checkParts(t, tt.name, parts, tt.max, tt.name != "small max")
+	}
+}
+
+func TestSplitMessageFences(t *testing.T) {
+	code := "```go\n" + strings.Repeat("fmt.Println(\"hello\")\n", 150) + "```"
+	parts := SplitMessage(code, 2000)
+	if len(parts) < 2 {
+		t.Fatalf("got %d parts, want at least 2", len(parts))
+	}
+	for i, part := range parts {
+		if !strings.HasPrefix(part, "```go\n") {
+			t.Errorf("part %d does not open the code block with its language:\n%.40s", i, part)
+		}
+		if !strings.HasSuffix(part, "\n```") {
+			t.Errorf("part %d does not close the code block", i)
+		}
+	}
+
+	// Only the language is kept when a block is reopened
+	code = "```go title=main.go\n" + strings.Repeat("fmt.Println(\"hello\")\n", 150) + "```"
+	parts = SplitMessage(code, 2000)
+	if !strings.HasPrefix(parts[1], "```go\n") {
+		t.Errorf("part 1 reopens the code block with %q, want ```go", strings.SplitN(parts[1], "\n", 2)[0])
+	}
+
+	// A fence that opens and closes on one line does not wrap the rest in a code block
+	parts = SplitMessage("```make test```\n"+strings.Repeat("a line of text\n", 200), 2000)
+	if len(parts) != 2 || strings.HasPrefix(parts[1], codeFence) || strings.HasSuffix(parts[0], "\n"+codeFence) {
+		t.Errorf("a one line fence was treated as an open code block: %.40q", parts)
+	}
+}
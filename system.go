@@ -0,0 +1,120 @@
+package discordterm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	. "github.com/logrusorgru/aurora"
+)
+
+// Message types that discordgo does not define, numbered as in
+// https://discord.com/developers/docs/resources/channel#message-object-message-types
+const (
+	messageTypeDiscoveryGracePeriodInitial   discordgo.MessageType = 16
+	messageTypeDiscoveryGracePeriodFinal     discordgo.MessageType = 17
+	messageTypeGuildInviteReminder           discordgo.MessageType = 22
+	messageTypeAutoModerationAction          discordgo.MessageType = 24
+	messageTypeRoleSubscriptionPurchase      discordgo.MessageType = 25
+	messageTypeStageStart                    discordgo.MessageType = 27
+	messageTypeStageEnd                      discordgo.MessageType = 28
+	messageTypeStageSpeaker                  discordgo.MessageType = 29
+	messageTypeStageTopic                    discordgo.MessageType = 31
+	messageTypeApplicationPremiumSubscribed  discordgo.MessageType = 32
+	messageTypeGuildIncidentAlertModeEnabled discordgo.MessageType = 36
+)
+
+// SystemMessageText returns the text of an event for system messages
+// Such as "alice pinned a message". ok is false for regular messages.
+func (c *Client) SystemMessageText(m *discordgo.Message, conf *Config) (text string, ok bool) {
+	name := c.DisplayName(m.ChannelID, m.Author, conf)
+
+	// The user a group DM change was applied to
+	target := "someone"
+	if len(m.Mentions) > 0 {
+		target = c.DisplayName(m.ChannelID, m.Mentions[0], conf)
+	}
+
+	switch m.Type {
+	case discordgo.MessageTypeRecipientAdd:
+		return fmt.Sprintf("%s added %s to the group", name, target), true
+	case discordgo.MessageTypeRecipientRemove:
+		if len(m.Mentions) > 0 && m.Author != nil && m.Mentions[0].ID == m.Author.ID {
+			return name + " left the group", true
+		}
+		return fmt.Sprintf("%s removed %s from the group", name, target), true
+	case discordgo.MessageTypeCall:
+		return name + " started a call", true
+	case discordgo.MessageTypeChannelNameChange:
+		return fmt.Sprintf("%s changed the channel name: %s", name, m.Content), true
+	case discordgo.MessageTypeChannelIconChange:
+		return name + " changed the channel icon", true
+	case discordgo.MessageTypeChannelPinnedMessage:
+		if m.MessageReference != nil && m.MessageReference.MessageID != "" {
+			return fmt.Sprintf("%s pinned message %s", name, m.MessageReference.MessageID), true
+		}
+		return name + " pinned a message", true
+	case discordgo.MessageTypeGuildMemberJoin:
+		return name + " joined the server", true
+	case discordgo.MessageTypeUserPremiumGuildSubscription,
+		discordgo.MessageTypeUserPremiumGuildSubscriptionTierOne,
+		discordgo.MessageTypeUserPremiumGuildSubscriptionTierTwo,
+		discordgo.MessageTypeUserPremiumGuildSubscriptionTierThree:
+		// The content holds the number of boosts when there was more than one
+		text = name + " boosted the server"
+		if m.Content != "" && m.Content != "1" {
+			text += " " + m.Content + " times"
+		}
+		if m.Type != discordgo.MessageTypeUserPremiumGuildSubscription {
+			level := int(m.Type - discordgo.MessageTypeUserPremiumGuildSubscription)
+			text += fmt.Sprintf(", the server reached level %d", level)
+		}
+		return text, true
+	case discordgo.MessageTypeChannelFollowAdd:
+		return fmt.Sprintf("%s added %s to this channel", name, m.Content), true
+	case discordgo.MessageTypeGuildDiscoveryDisqualified:
+		return "This server was removed from server discovery", true
+	case discordgo.MessageTypeGuildDiscoveryRequalified:
+		return "This server is eligible for server discovery again", true
+	case messageTypeDiscoveryGracePeriodInitial, messageTypeDiscoveryGracePeriodFinal:
+		return "This server has failed discovery activity requirements", true
+	case discordgo.MessageTypeThreadCreated:
+		return fmt.Sprintf("%s started a thread: %s", name, m.Content), true
+	case messageTypeGuildInviteReminder:
+		return "Invite your friends to this server", true
+	case messageTypeAutoModerationAction:
+		return fmt.Sprintf("AutoMod blocked a message from %s", name), true
+	case messageTypeRoleSubscriptionPurchase:
+		return name + " joined a role subscription", true
+	case messageTypeStageStart:
+		return fmt.Sprintf("%s started the stage: %s", name, m.Content), true
+	case messageTypeStageEnd:
+		return fmt.Sprintf("%s ended the stage: %s", name, m.Content), true
+	case messageTypeStageSpeaker:
+		return name + " is now a speaker", true
+	case messageTypeStageTopic:
+		return fmt.Sprintf("%s changed the stage topic: %s", name, m.Content), true
+	case messageTypeApplicationPremiumSubscribed:
+		return name + " upgraded an app", true
+	case messageTypeGuildIncidentAlertModeEnabled:
+		return "Raid protection was enabled", true
+	}
+	return "", false
+}
+
+// PrintSystemMessage prints a system message as a one line event.
+// It returns false if the message is a regular message.
+func (c *Client) PrintSystemMessage(m *discordgo.Message, conf *Config) bool {
+	text, ok := c.SystemMessageText(m, conf)
+	if !ok {
+		return false
+	}
+	text = strings.TrimSpace(text)
+	if conf.ColorText {
+		fmt.Println(Brown("*"), Brown(text), "\t", Blue(m.ID))
+	} else {
+		fmt.Println("*", text, "\t", m.ID)
+	}
+	fmt.Println()
+	return true
+}
@@ -0,0 +1,172 @@
+package discordterm
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// DefaultAutoArchiveDuration is the number of minutes of inactivity
+// After which new threads are archived
+const DefaultAutoArchiveDuration = 1440
+
+// Thread is a thread channel
+type Thread struct {
+	*discordgo.Channel
+}
+
+// Archived returns true if the thread is archived
+func (t *Thread) Archived() bool {
+	return t.ThreadMetadata != nil && t.ThreadMetadata.Archived
+}
+
+// LastActivity returns the time of the last message in the thread,
+// Or when it was created if it has no messages
+func (t *Thread) LastActivity() time.Time {
+	id := t.LastMessageID
+	if id == "" {
+		id = t.ID
+	}
+	ts, _ := discordgo.SnowflakeTimestamp(id)
+	return ts
+}
+
+// IsThreadType returns true for thread channel types
+func IsThreadType(t discordgo.ChannelType) bool {
+	return t == discordgo.ChannelTypeGuildNewsThread || t == discordgo.ChannelTypeGuildPublicThread || t == discordgo.ChannelTypeGuildPrivateThread
+}
+
+// Thread returns a thread from the state by ID
+func (c *Client) Thread(threadID string) (*Thread, bool) {
+	ch, err := c.Cli.State.Channel(threadID)
+	if err != nil || !IsThreadType(ch.Type) {
+		return nil, false
+	}
+	return &Thread{ch}, true
+}
+
+// KnownThreads returns the threads of a channel that have been received
+// From discord so far, newest first
+func (c *Client) KnownThreads(channelID string) []*Thread {
+	threads := []*Thread{}
+	channel, err := c.Cli.State.Channel(channelID)
+	if err != nil {
+		return threads
+	}
+	guild, err := c.Cli.State.Guild(channel.GuildID)
+	if err != nil {
+		return threads
+	}
+	c.Cli.State.RLock()
+	for _, t := range guild.Threads {
+		if t.ParentID == channelID {
+			threads = append(threads, &Thread{t})
+		}
+	}
+	c.Cli.State.RUnlock()
+	sortThreads(threads)
+	return threads
+}
+
+// addThreads adds threads fetched from the API to the state so that they
+// Can be used like any other channel
+func (c *Client) addThreads(threads []*discordgo.Channel) {
+	for _, t := range threads {
+		c.Cli.State.ChannelAdd(t)
+	}
+}
+
+// sortThreads orders threads with active threads first, then by
+// Most recent activity
+func sortThreads(threads []*Thread) {
+	sort.SliceStable(threads, func(i, j int) bool {
+		if threads[i].Archived() != threads[j].Archived() {
+			return !threads[i].Archived()
+		}
+		a, b := threads[i].LastMessageID, threads[j].LastMessageID
+		if a == "" {
+			a = threads[i].ID
+		}
+		if b == "" {
+			b = threads[j].ID
+		}
+		if len(a) != len(b) {
+			return len(a) > len(b)
+		}
+		return a > b
+	})
+}
+
+// Channel returns a channel from the state, falling back to the API.
+// Threads fetched from the API are added to the state.
+func (c *Client) Channel(channelID string) (*discordgo.Channel, error) {
+	if ch, err := c.Cli.State.Channel(channelID); err == nil {
+		return ch, nil
+	}
+	ch, err := c.Cli.Channel(channelID)
+	if err != nil {
+		return nil, err
+	}
+	if IsThreadType(ch.Type) {
+		c.addThreads([]*discordgo.Channel{ch})
+	}
+	return ch, nil
+}
+
+// ChannelThreads fetches the active and archived public threads of a channel
+func (c *Client) ChannelThreads(channelID string) ([]*Thread, error) {
+	channel, err := c.Channel(channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	channels := []*discordgo.Channel{}
+	if active, err := c.Cli.GuildThreadsActive(channel.GuildID); err == nil {
+		channels = append(channels, active.Threads...)
+	} else {
+		// Only bots may list the active threads of a guild, use the threads received so far instead
+		log.Println(err)
+		for _, t := range c.KnownThreads(channelID) {
+			channels = append(channels, t.Channel)
+		}
+	}
+	archived, err := c.Cli.ThreadsArchived(channelID, nil, 50)
+	if err != nil {
+		return nil, err
+	}
+	channels = append(channels, archived.Threads...)
+
+	seen := map[string]bool{}
+	threads := []*Thread{}
+	for _, t := range channels {
+		if t.ParentID != channelID || seen[t.ID] {
+			continue
+		}
+		seen[t.ID] = true
+		threads = append(threads, &Thread{t})
+	}
+	c.addThreads(channels)
+	sortThreads(threads)
+	return threads, nil
+}
+
+// StartThread starts a public thread in a channel. If messageID is not empty
+// The thread is started from that message.
+func (c *Client) StartThread(channelID, messageID, name string) (*Thread, error) {
+	var (
+		ch  *discordgo.Channel
+		err error
+	)
+	if messageID != "" {
+		ch, err = c.Cli.MessageThreadStart(channelID, messageID, name, DefaultAutoArchiveDuration)
+	} else {
+		ch, err = c.Cli.ThreadStart(channelID, name, discordgo.ChannelTypeGuildPublicThread, DefaultAutoArchiveDuration)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.addThreads([]*discordgo.Channel{ch})
+	return &Thread{ch}, nil
+}
@@ -0,0 +1,118 @@
+package discordterm
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// TypingTimeout is how long a user is shown as typing after a typing event
+const TypingTimeout = 10 * time.Second
+
+// typingInterval is how often a typing event is sent while composing a message.
+// Discord shows a typing indicator for 10 seconds after each event
+const typingInterval = 8 * time.Second
+
+// TypingUsers returns the display names of the users typing in a channel
+func (c *Client) TypingUsers(channelID string) []string {
+	c.Lock()
+	users := []string{}
+	for userID, t := range c.typing[channelID] {
+		if time.Since(t) < TypingTimeout {
+			users = append(users, userID)
+		} else {
+			delete(c.typing[channelID], userID)
+		}
+	}
+	c.Unlock()
+
+	names := []string{}
+	for _, userID := range users {
+		if u, err := c.user(channelID, userID); err == nil {
+			names = append(names, c.DisplayName(channelID, u, c.Conf))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// user finds a user from the member list of a channel's guild
+func (c *Client) user(channelID, userID string) (*discordgo.User, error) {
+	channel, err := c.Cli.State.Channel(channelID)
+	if err != nil {
+		return nil, err
+	}
+	member, err := c.Cli.State.Member(channel.GuildID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return member.User, nil
+}
+
+// FormatTyping formats a list of names as "alice and bob are typing…"
+func FormatTyping(names []string) string {
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return names[0] + " is typing…"
+	case 2, 3:
+		return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1] + " are typing…"
+	default:
+		return "Several people are typing…"
+	}
+}
+
+// setTyping records that a user started or stopped typing in a channel
+func (c *Client) setTyping(channelID, userID string, typing bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	m, ok := c.typing[channelID]
+	if !ok {
+		m = map[string]time.Time{}
+		c.typing[channelID] = m
+	}
+	if typing {
+		m[userID] = time.Now()
+	} else {
+		delete(m, userID)
+	}
+}
+
+// SendTyping tells other users that we are typing in a channel.
+// Events are only sent every few seconds no matter how often it is called.
+func (c *Client) SendTyping(channelID string) {
+	c.Lock()
+	if c.lastTypingChannel == channelID && time.Since(c.lastTyping) < typingInterval {
+		c.Unlock()
+		return
+	}
+	c.lastTyping = time.Now()
+	c.lastTypingChannel = channelID
+	c.Unlock()
+
+	go c.Cli.ChannelTyping(channelID)
+}
+
+// StartTyping sends typing events to a channel until the returned function is called
+func (c *Client) StartTyping(channelID string) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(typingInterval)
+		defer ticker.Stop()
+
+		c.Cli.ChannelTyping(channelID)
+		for {
+			select {
+			case <-ticker.C:
+				c.Cli.ChannelTyping(channelID)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
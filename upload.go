@@ -0,0 +1,129 @@
+package discordterm
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// MaxUploadFiles is the largest number of files that can be sent in one message
+const MaxUploadFiles = 10
+
+// DefaultUploadLimit is the upload size limit of channels in unboosted guilds
+// And private channels
+const DefaultUploadLimit = 10 * 1024 * 1024
+
+// SpoilerPrefix marks an attachment as a spoiler when it begins its filename
+const SpoilerPrefix = "SPOILER_"
+
+// UploadLimit returns the size limit in bytes of the files that can be
+// Uploaded to a channel
+func (c *Client) UploadLimit(channelID string) int64 {
+	channel, err := c.Cli.State.Channel(channelID)
+	if err != nil {
+		return DefaultUploadLimit
+	}
+	guild, err := c.Cli.State.Guild(channel.GuildID)
+	if err != nil {
+		return DefaultUploadLimit
+	}
+
+	switch guild.PremiumTier {
+	case discordgo.PremiumTier3:
+		return 100 * 1024 * 1024
+	case discordgo.PremiumTier2:
+		return 50 * 1024 * 1024
+	default:
+		return DefaultUploadLimit
+	}
+}
+
+// Upload is a file queued for upload
+type Upload struct {
+	Path string
+	Name string
+	Size int64
+	Err  error
+
+	file *os.File
+}
+
+// OpenUploads opens the files matched by a list of paths and glob patterns.
+// Files that cannot be uploaded have their Err field set.
+// Close must be called on the returned uploads once they are sent.
+func OpenUploads(patterns []string, limit int64, spoiler bool) []*Upload {
+	uploads := []*Upload{}
+	for _, pattern := range patterns {
+		paths, err := filepath.Glob(pattern)
+		if err != nil || len(paths) == 0 {
+			// Not a glob, or a path that does not exist
+			paths = []string{pattern}
+		}
+		for _, path := range paths {
+			uploads = append(uploads, openUpload(path, limit, spoiler))
+		}
+	}
+	return uploads
+}
+
+func openUpload(path string, limit int64, spoiler bool) *Upload {
+	u := &Upload{Path: path, Name: filepath.Base(path)}
+	if spoiler && !strings.HasPrefix(u.Name, SpoilerPrefix) {
+		u.Name = SpoilerPrefix + u.Name
+	}
+
+	finfo, err := os.Stat(path)
+	if err != nil {
+		u.Err = err
+		return u
+	}
+	if finfo.IsDir() {
+		u.Err = errors.New("is a directory")
+		return u
+	}
+	u.Size = finfo.Size()
+	if u.Size > limit {
+		u.Err = fmt.Errorf("file is %s, the upload limit is %s", FormatBytes(u.Size), FormatBytes(limit))
+		return u
+	}
+
+	u.file, err = os.Open(path)
+	if err != nil {
+		u.Err = err
+	}
+	return u
+}
+
+// Close closes the upload's file
+func (u *Upload) Close() {
+	if u.file != nil {
+		u.file.Close()
+	}
+}
+
+// SendUploads sends a group of opened uploads in a single message.
+// Uploads whose Err field is set are skipped.
+func (c *Client) SendUploads(channelID, caption string, uploads []*Upload) (*discordgo.Message, error) {
+	data := &discordgo.MessageSend{
+		Content: caption,
+	}
+	for _, u := range uploads {
+		if u.Err != nil || u.file == nil {
+			continue
+		}
+		data.Files = append(data.Files, &discordgo.File{
+			Name:        u.Name,
+			ContentType: mime.TypeByExtension(filepath.Ext(u.Name)),
+			Reader:      u.file,
+		})
+	}
+	if len(data.Files) == 0 {
+		return nil, errors.New("no files to upload")
+	}
+	return c.Cli.ChannelMessageSendComplex(channelID, data)
+}
@@ -0,0 +1,112 @@
+package discordterm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	. "github.com/logrusorgru/aurora"
+)
+
+// VoiceMember is a user connected to a voice channel
+type VoiceMember struct {
+	*discordgo.VoiceState
+	Name string
+}
+
+// Flags returns readable flags such as "muted" and "live" for the member's voice state
+func (v *VoiceMember) Flags() []string {
+	flags := []string{}
+	switch {
+	case v.Mute:
+		flags = append(flags, "server muted")
+	case v.SelfMute:
+		flags = append(flags, "muted")
+	}
+	switch {
+	case v.Deaf:
+		flags = append(flags, "server deafened")
+	case v.SelfDeaf:
+		flags = append(flags, "deafened")
+	}
+	if v.SelfStream {
+		flags = append(flags, "live")
+	}
+	if v.SelfVideo {
+		flags = append(flags, "video")
+	}
+	if v.Suppress {
+		flags = append(flags, "audience")
+	}
+	return flags
+}
+
+// VoiceMembers returns the users connected to a voice channel, ordered by name
+func (c *Client) VoiceMembers(guildID, channelID string) []*VoiceMember {
+	guild, err := c.Cli.State.Guild(guildID)
+	if err != nil {
+		return nil
+	}
+
+	c.Cli.State.RLock()
+	states := []*discordgo.VoiceState{}
+	for _, vs := range guild.VoiceStates {
+		if vs.ChannelID == channelID {
+			states = append(states, vs)
+		}
+	}
+	c.Cli.State.RUnlock()
+
+	members := []*VoiceMember{}
+	for _, vs := range states {
+		vm := &VoiceMember{VoiceState: vs, Name: vs.UserID}
+		if m, err := c.Member(guildID, vs.UserID); err == nil {
+			vm.Name = c.DisplayName(channelID, m.User, c.Conf)
+		}
+		members = append(members, vm)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return strings.ToLower(members[i].Name) < strings.ToLower(members[j].Name)
+	})
+	return members
+}
+
+// PrintVoiceStateUpdate prints a notice when a user joins, leaves
+// Or moves between voice channels
+func (c *Client) PrintVoiceStateUpdate(v *discordgo.VoiceStateUpdate, conf *Config) {
+	before := ""
+	if v.BeforeUpdate != nil {
+		before = v.BeforeUpdate.ChannelID
+	}
+	if before == v.ChannelID {
+		return
+	}
+
+	name := v.UserID
+	if m, err := c.Member(v.GuildID, v.UserID); err == nil {
+		name = c.DisplayName(v.ChannelID, m.User, conf)
+	}
+	channelName := func(id string) string {
+		if ch, err := c.Cli.State.Channel(id); err == nil {
+			return ChannelTypeGlyph(ch.Type) + " " + ch.Name
+		}
+		return id
+	}
+
+	var text string
+	switch {
+	case before == "":
+		text = fmt.Sprintf("%s joined %s", name, channelName(v.ChannelID))
+	case v.ChannelID == "":
+		text = fmt.Sprintf("%s left %s", name, channelName(before))
+	default:
+		text = fmt.Sprintf("%s moved from %s to %s", name, channelName(before), channelName(v.ChannelID))
+	}
+
+	if conf.ColorText {
+		fmt.Println(Brown("*"), Brown(text))
+	} else {
+		fmt.Println("*", text)
+	}
+}